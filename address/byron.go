@@ -6,7 +6,9 @@ import (
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/fxamacker/cbor/v2"
+	"github.com/milos-ethernal/go-cardano-serialization/crypto"
 	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"golang.org/x/crypto/sha3"
 )
 
 var (
@@ -25,6 +27,31 @@ type ByronAddress struct {
 	Tag        uint
 }
 
+// NewByronAddress returns the Icarus style Byron address of the extended public key (public key and chain code)
+// with the address attributes.
+func NewByronAddress(publicKey, chainCode []byte, attributes ByronAddressAttributes) (*ByronAddress, error) {
+	xpub := append(append([]byte{}, publicKey...), chainCode...)
+
+	// the address root is blake2b224(sha3_256([address type, [spending data type, xpub], attributes]))
+	root, err := cbor.Marshal([]interface{}{uint(0), []interface{}{uint(0), xpub}, attributes})
+	if err != nil {
+		return nil, err
+	}
+	sha := sha3.Sum256(root)
+	hash := crypto.Blake2b224(sha[:])
+
+	return &ByronAddress{
+		Hash:       hash[:],
+		Attributes: attributes,
+		Tag:        0,
+	}, nil
+}
+
+// AttributesBytes returns the cbor encoded attributes of the address.
+func (b *ByronAddress) AttributesBytes() ([]byte, error) {
+	return cbor.Marshal(b.Attributes)
+}
+
 // Bytes returns byte slice represantation of the Address.
 func (b *ByronAddress) Bytes() (bytes []byte) {
 	bytes, _ = b.MarshalCBOR()
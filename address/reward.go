@@ -28,9 +28,9 @@ func (r *RewardAddress) String() string {
 	return str
 }
 
-// MarshalCBOR returns a cbor encoded byte slice of the base address.
+// MarshalCBOR returns a cbor encoded byte slice of the reward address.
 func (r *RewardAddress) MarshalCBOR() ([]byte, error) {
-	return cbor.Marshal(r.Bytes)
+	return cbor.Marshal(r.Bytes())
 }
 
 // NetworkInfo returns pointer to NetworkInfo{ProtocolMagigic and NetworkId}.
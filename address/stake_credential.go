@@ -1,5 +1,11 @@
 package address
 
+import (
+	"errors"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
 type StakeCredentialType byte
 
 const (
@@ -8,8 +14,8 @@ const (
 )
 
 type StakeCredential struct {
-	Kind    StakeCredentialType `cbor:"0,keyasint,omitempty"`
-	Payload []byte              `cbor:"1,keyasint,omitempty"`
+	Kind    StakeCredentialType
+	Payload []byte
 }
 
 func NewKeyStakeCredential(hash []byte) *StakeCredential {
@@ -25,3 +31,33 @@ func NewScriptStakeCredential(hash []byte) *StakeCredential {
 		Payload: hash,
 	}
 }
+
+type arrayStakeCredential struct {
+	_       struct{} `cbor:",toarray"`
+	Kind    StakeCredentialType
+	Payload []byte
+}
+
+// MarshalCBOR returns the cbor encoding [kind, hash] of the stake credential as used in certificates.
+func (s *StakeCredential) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(arrayStakeCredential{
+		Kind:    s.Kind,
+		Payload: s.Payload,
+	})
+}
+
+// UnmarshalCBOR deserializes a cbor encoded [kind, hash] into a stake credential.
+func (s *StakeCredential) UnmarshalCBOR(data []byte) error {
+	var cred arrayStakeCredential
+	if err := cbor.Unmarshal(data, &cred); err != nil {
+		return err
+	}
+	if cred.Kind > ScriptStakeCredentialType {
+		return errors.New("invalid stake credential type")
+	}
+
+	s.Kind = cred.Kind
+	s.Payload = cred.Payload
+
+	return nil
+}
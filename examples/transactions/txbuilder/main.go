@@ -155,7 +155,7 @@ func main() {
 	}
 
 	// Set TTL for 5 min into the future
-	builder.SetTTL(uint32(tip.Slot) + uint32(300))
+	builder.SetTTL(uint64(tip.Slot) + uint64(300))
 
 	// Route back the change to the source address
 	// This is equivalent to adding an output with the source address and change amount
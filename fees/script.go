@@ -0,0 +1,90 @@
+package fees
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// The price per byte of reference scripts increases by refScriptCostMultiplier every refScriptCostStride bytes.
+var refScriptCostMultiplier = big.NewRat(6, 5)
+
+const refScriptCostStride = 25600
+
+// Calculator is implemented by the fee equations used to compute the minimum fee of a transaction.
+type Calculator interface {
+	// MinFee returns the minimum fee (in lovelaces) of a transaction of txSize bytes running scripts with
+	// mem memory units and steps CPU steps in total, and using refScriptSize bytes of reference scripts.
+	MinFee(txSize uint, mem, steps uint64, refScriptSize uint) uint
+}
+
+// MinFee returns the fee of the linear equation. Script execution and reference scripts are not charged.
+func (l *LinearFee) MinFee(txSize uint, _, _ uint64, _ uint) uint {
+	return l.TxFeeFixed + l.TxFeePerByte*txSize
+}
+
+// ScriptFee contains the parameters for the fee equation of transactions running Plutus scripts
+// `linear fee + PriceMem * mem + PriceSteps * steps + reference script fee`.
+// These are provided in the protocol parameters
+type ScriptFee struct {
+	LinearFee
+
+	// The price of a memory unit (in lovelaces).
+	PriceMem float64
+
+	// The price of a CPU step (in lovelaces).
+	PriceSteps float64
+
+	// The price of a byte of reference scripts (in lovelaces) for the first 25600 bytes.
+	// Every following 25600 bytes cost 1.2 times more per byte than the previous ones.
+	MinFeeRefScriptCostPerByte float64
+}
+
+// NewScriptFee returns a pointer to a new ScriptFee from the provided params
+func NewScriptFee(linearFee *LinearFee, priceMem, priceSteps, minFeeRefScriptCostPerByte float64) *ScriptFee {
+	return &ScriptFee{
+		LinearFee:                  *linearFee,
+		PriceMem:                   priceMem,
+		PriceSteps:                 priceSteps,
+		MinFeeRefScriptCostPerByte: minFeeRefScriptCostPerByte,
+	}
+}
+
+// MinFee returns the sum of the linear fee, the execution fee and the reference script fee.
+func (s *ScriptFee) MinFee(txSize uint, mem, steps uint64, refScriptSize uint) uint {
+	return s.LinearFee.MinFee(txSize, mem, steps, refScriptSize) + s.ExecutionFee(mem, steps) + s.ReferenceScriptFee(refScriptSize)
+}
+
+// ExecutionFee returns the fee for mem memory units and steps CPU steps, rounded up.
+func (s *ScriptFee) ExecutionFee(mem, steps uint64) uint {
+	fee := new(big.Rat).Mul(rat(s.PriceMem), new(big.Rat).SetUint64(mem))
+	fee.Add(fee, new(big.Rat).Mul(rat(s.PriceSteps), new(big.Rat).SetUint64(steps)))
+
+	q, r := new(big.Int).QuoRem(fee.Num(), fee.Denom(), new(big.Int))
+	if r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return uint(q.Uint64())
+}
+
+// ReferenceScriptFee returns the tiered fee for size bytes of reference scripts, rounded down.
+func (s *ScriptFee) ReferenceScriptFee(size uint) uint {
+	fee := new(big.Rat)
+	price := rat(s.MinFeeRefScriptCostPerByte)
+	for ; size >= refScriptCostStride; size -= refScriptCostStride {
+		fee.Add(fee, new(big.Rat).Mul(price, big.NewRat(refScriptCostStride, 1)))
+		price = new(big.Rat).Mul(price, refScriptCostMultiplier)
+	}
+	fee.Add(fee, new(big.Rat).Mul(price, new(big.Rat).SetUint64(uint64(size))))
+
+	return uint(new(big.Int).Quo(fee.Num(), fee.Denom()).Uint64())
+}
+
+// rat returns the decimal value of a price as written in the protocol parameters, e.g. 0.0577 rather
+// than the closest float64, so that rounding matches the ledger.
+func rat(f float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return new(big.Rat).SetFloat64(f)
+	}
+	return r
+}
@@ -3,6 +3,8 @@ package node
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/blockfrost/blockfrost-go"
 	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/crypto"
 	"github.com/milos-ethernal/go-cardano-serialization/network"
 	"github.com/milos-ethernal/go-cardano-serialization/protocol"
 	"github.com/milos-ethernal/go-cardano-serialization/tx"
@@ -20,6 +23,47 @@ type blockfrostNode struct {
 	network   *network.NetworkInfo
 	client    blockfrost.APIClient
 	projectId string
+	serverUrl string
+}
+
+// epochParameters are the protocol parameters returned by the Blockfrost API, including the
+// Alonzo and Conway parameters missing from blockfrost.EpochParameters.
+type epochParameters struct {
+	blockfrost.EpochParameters
+
+	CoinsPerUtxoSize           string              `json:"coins_per_utxo_size"`
+	CostModelsRaw              protocol.CostModels `json:"cost_models_raw"`
+	PriceMem                   float64             `json:"price_mem"`
+	PriceStep                  float64             `json:"price_step"`
+	CollateralPercent          uint                `json:"collateral_percent"`
+	MaxCollateralInputs        uint                `json:"max_collateral_inputs"`
+	MinFeeRefScriptCostPerByte float64             `json:"min_fee_ref_script_cost_per_byte"`
+	DRepDeposit                string              `json:"drep_deposit"`
+	GovActionDeposit           string              `json:"gov_action_deposit"`
+}
+
+// addressUTxO is an UTxO returned by the Blockfrost API, including the inline datum and the reference script
+// missing from blockfrost.AddressUTXO.
+type addressUTxO struct {
+	blockfrost.AddressUTXO
+
+	InlineDatum         *string `json:"inline_datum"`
+	ReferenceScriptHash *string `json:"reference_script_hash"`
+}
+
+var plutusVersions = map[string]tx.PlutusVersion{
+	"plutusV1": tx.PlutusV1,
+	"plutusV2": tx.PlutusV2,
+	"plutusV3": tx.PlutusV3,
+}
+
+// parseLovelace parses an amount of the Blockfrost API, which is empty for parameters of later eras.
+func parseLovelace(amount string) (uint, error) {
+	if amount == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseUint(amount, 10, 64)
+	return uint(value), err
 }
 
 func (b *blockfrostNode) getNetwork() (serverUrl string) {
@@ -33,18 +77,16 @@ func (b *blockfrostNode) getNetwork() (serverUrl string) {
 }
 
 // UTXOs queries the network for Unspent Transaction Outputs belonging to an address.
+// The datums and reference scripts of the outputs are returned along with their value.
+// The SDK does not decode inline datums and reference scripts, so the endpoint is queried directly.
 func (b *blockfrostNode) UTXOs(addr address.Address) (txIs []tx.TxInput, err error) {
-	utxos, err := b.client.AddressUTXOs(
-		context.TODO(),
-		addr.String(),
-		blockfrost.APIQueryParams{},
-	)
-	if err != nil {
+	var utxos []addressUTxO
+	if err = b.get(fmt.Sprintf("addresses/%s/utxos", addr.String()), &utxos); err != nil {
 		return
 	}
 
 	for _, utxo := range utxos {
-		var amount uint
+		value := tx.NewValue(0)
 		for _, am := range utxo.Amount {
 			if am.Unit == "lovelace" {
 
@@ -52,19 +94,177 @@ func (b *blockfrostNode) UTXOs(addr address.Address) (txIs []tx.TxInput, err err
 				if err != nil {
 					return []tx.TxInput{}, err
 				}
-				amount = uint(amountI)
+				value.Amount = uint(amountI)
+				continue
 			}
 
+			// Native token units are the hex encoded policy id followed by the hex encoded asset name
+			quantity, err := strconv.ParseUint(am.Quantity, 10, 64)
+			if err != nil {
+				return []tx.TxInput{}, err
+			}
+			unit, err := hex.DecodeString(am.Unit)
+			if err != nil {
+				return []tx.TxInput{}, err
+			}
+			if len(unit) < crypto.ScriptHashLen {
+				return []tx.TxInput{}, fmt.Errorf("invalid asset unit %q: shorter than a policy id", am.Unit)
+			}
+			policy, err := crypto.ScriptHashFromBytes(unit[:crypto.ScriptHashLen])
+			if err != nil {
+				return []tx.TxInput{}, err
+			}
+			value = value.Add(tx.NewValueWithAssets(0, tx.MultiAsset{
+				policy: tx.Assets{tx.AssetName(unit[crypto.ScriptHashLen:]): quantity},
+			}))
 		}
-		txIs = append(txIs, *tx.NewTxInput(utxo.TxHash, uint16(utxo.OutputIndex), amount))
+
+		output := tx.NewTxOutputWithValue(addr, value)
+		switch {
+		case utxo.InlineDatum != nil:
+			data, err := hex.DecodeString(*utxo.InlineDatum)
+			if err != nil {
+				return []tx.TxInput{}, err
+			}
+			datum, err := tx.NewPlutusDataFromCBOR(data)
+			if err != nil {
+				return []tx.TxInput{}, fmt.Errorf("invalid inline datum of %s#%d: %w", utxo.TxHash, utxo.OutputIndex, err)
+			}
+			output.Datum = &datum
+		case utxo.DataHash != "":
+			output.DatumHash, err = hex.DecodeString(utxo.DataHash)
+			if err != nil {
+				return []tx.TxInput{}, err
+			}
+		}
+		if utxo.ReferenceScriptHash != nil {
+			output.ScriptRef, err = b.scriptRef(*utxo.ReferenceScriptHash)
+			if err != nil {
+				return []tx.TxInput{}, err
+			}
+		}
+
+		txIs = append(txIs, *tx.NewTxInputFromOutput(utxo.TxHash, uint16(utxo.OutputIndex), output))
 	}
 
 	return
 }
 
+// scriptRef queries the script of the hash, checking that the returned script has this hash.
+func (b *blockfrostNode) scriptRef(scriptHash string) (*tx.ScriptRef, error) {
+	var script struct {
+		Type string `json:"type"`
+	}
+	if err := b.get("scripts/"+scriptHash, &script); err != nil {
+		return nil, err
+	}
+
+	var ref *tx.ScriptRef
+	var hash tx.Hash28
+	version, isPlutus := plutusVersions[script.Type]
+	switch {
+	case isPlutus:
+		plutusScript, err := b.plutusScript(scriptHash, version)
+		if err != nil {
+			return nil, err
+		}
+		ref = tx.NewPlutusScriptRef(plutusScript)
+		if hash, err = plutusScript.Hash(); err != nil {
+			return nil, err
+		}
+	case script.Type == "timelock":
+		var res struct {
+			JSON *tx.NativeScript `json:"json"`
+		}
+		if err := b.get(fmt.Sprintf("scripts/%s/json", scriptHash), &res); err != nil {
+			return nil, err
+		}
+		if res.JSON == nil {
+			return nil, fmt.Errorf("no json for native script %s", scriptHash)
+		}
+		ref = tx.NewNativeScriptRef(*res.JSON)
+		var err error
+		if hash, err = res.JSON.Hash(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %q of script %s", script.Type, scriptHash)
+	}
+
+	if hex.EncodeToString(hash) != scriptHash {
+		return nil, fmt.Errorf("script %s has hash %x", scriptHash, hash)
+	}
+	return ref, nil
+}
+
+// plutusScript queries the serialized Plutus script of the hash.
+func (b *blockfrostNode) plutusScript(scriptHash string, version tx.PlutusVersion) (tx.PlutusScript, error) {
+	var res struct {
+		CBOR string `json:"cbor"`
+	}
+	if err := b.get(fmt.Sprintf("scripts/%s/cbor", scriptHash), &res); err != nil {
+		return tx.PlutusScript{}, err
+	}
+	data, err := hex.DecodeString(res.CBOR)
+	if err != nil {
+		return tx.PlutusScript{}, err
+	}
+
+	script := tx.NewPlutusScript(version, data)
+	// the script may also be returned as the cardano-cli cborHex, wrapped in one more byte string
+	if hash, err := script.Hash(); err == nil && hex.EncodeToString(hash) != scriptHash {
+		if unwrapped, err := tx.NewPlutusScriptFromCBORHex(version, res.CBOR); err == nil {
+			return unwrapped, nil
+		}
+	}
+	return script, nil
+}
+
+// latestEpochParameters queries the protocol parameters of the latest epoch.
+// The SDK does not decode the script and governance parameters, so the endpoint is queried directly.
+func (b *blockfrostNode) latestEpochParameters() (params epochParameters, err error) {
+	err = b.get("epochs/latest/parameters", &params)
+	return
+}
+
+// get queries the path of the Blockfrost API and decodes the JSON response into v.
+func (b *blockfrostNode) get(path string, v interface{}) (err error) {
+	req, err := http.NewRequestWithContext(
+		context.TODO(),
+		http.MethodGet,
+		fmt.Sprintf("%s/%s", b.serverUrl, path),
+		nil,
+	)
+	if err != nil {
+		return
+	}
+
+	req.Header.Add("project_id", b.projectId)
+
+	cli := &http.Client{}
+	res, err := cli.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	resb, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return
+	}
+
+	if res.StatusCode != 200 {
+		err = errors.New(string(resb))
+		return
+	}
+
+	err = json.Unmarshal(resb, v)
+	return
+}
+
 // ProtocolParameters queries the protocol parameters of the network.
 func (b *blockfrostNode) ProtocolParameters() (p protocol.Protocol, err error) {
-	params, err := b.client.LatestEpochParameters(context.TODO())
+	params, err := b.latestEpochParameters()
 	if err != nil {
 		return
 	}
@@ -74,15 +274,59 @@ func (b *blockfrostNode) ProtocolParameters() (p protocol.Protocol, err error) {
 		return
 	}
 
+	keyDeposit, err := strconv.Atoi(params.KeyDeposit)
+	if err != nil {
+		return
+	}
+
+	poolDeposit, err := strconv.Atoi(params.PoolDeposit)
+	if err != nil {
+		return
+	}
+
+	maxValueSize, err := parseLovelace(params.MaxValSize)
+	if err != nil {
+		return
+	}
+
+	utxoCostPerByte, err := parseLovelace(params.CoinsPerUtxoSize)
+	if err != nil {
+		return
+	}
+
+	drepDeposit, err := parseLovelace(params.DRepDeposit)
+	if err != nil {
+		return
+	}
+
+	govActionDeposit, err := parseLovelace(params.GovActionDeposit)
+	if err != nil {
+		return
+	}
+
 	return protocol.Protocol{
 		TxFeePerByte: uint(params.MinFeeA),
 		TxFeeFixed:   uint(params.MinFeeB),
 		MaxTxSize:    uint(params.MaxTxSize),
 		ProtocolVersion: protocol.ProtocolVersion{
-			uint8(params.ProtocolMajorVer),
-			uint8(params.ProtocolMinorVer),
+			Major: uint8(params.ProtocolMajorVer),
+			Minor: uint8(params.ProtocolMinorVer),
+		},
+		MinUTXOValue:         uint(minU),
+		UTxOCostPerByte:      utxoCostPerByte,
+		MaxValueSize:         maxValueSize,
+		StakeAddressDeposit:  uint(keyDeposit),
+		StakePoolDeposit:     uint(poolDeposit),
+		CostModels:           params.CostModelsRaw,
+		CollateralPercentage: params.CollateralPercent,
+		ExecutionUnitPrices: protocol.ExecutionUnitPrices{
+			PriceMemory: params.PriceMem,
+			PriceSteps:  params.PriceStep,
 		},
-		MinUTXOValue: uint(minU),
+		MaxCollateralInputs:        params.MaxCollateralInputs,
+		DRepDeposit:                drepDeposit,
+		GovActionDeposit:           govActionDeposit,
+		MinFeeRefScriptCostPerByte: params.MinFeeRefScriptCostPerByte,
 	}, nil
 }
 
@@ -144,6 +388,7 @@ func NewBlockfrostClient(projectId string, network *network.NetworkInfo) Node {
 		network:   network,
 		client:    client,
 		projectId: projectId,
+		serverUrl: serverUrl,
 	}
 
 }
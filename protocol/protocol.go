@@ -5,6 +5,15 @@ import (
 	"io/ioutil"
 )
 
+// CostModels maps the Plutus language names (PlutusV1, PlutusV2, PlutusV3) to their cost model parameters.
+type CostModels map[string][]int64
+
+// ExecutionUnitPrices contains the prices (in lovelaces) of the execution units used by Plutus scripts.
+type ExecutionUnitPrices struct {
+	PriceMemory float64 `json:"priceMemory"`
+	PriceSteps  float64 `json:"priceSteps"`
+}
+
 type ProtocolVersion struct {
 	Major uint8 `json:"major"`
 	Minor uint8 `json:"minor"`
@@ -27,6 +36,41 @@ type Protocol struct {
 
 	// Minimum UTXO Value
 	MinUTXOValue uint `json:"minUTxOValue"`
+
+	// The minimum lovelace of an output per byte of the output, plus 160 bytes of overhead.
+	// When set it replaces MinUTXOValue.
+	UTxOCostPerByte uint `json:"utxoCostPerByte"`
+
+	// The maximum size of the value of an output (in bytes).
+	MaxValueSize uint `json:"maxValueSize"`
+
+	// The deposit required to register a stake credential (in lovelace).
+	StakeAddressDeposit uint `json:"stakeAddressDeposit"`
+
+	// The deposit required to register a stake pool (in lovelace).
+	StakePoolDeposit uint `json:"stakePoolDeposit"`
+
+	// The cost models for Plutus script execution.
+	CostModels CostModels `json:"costModels"`
+
+	// The collateral required by transactions running Plutus scripts, as a percentage of the fee.
+	CollateralPercentage uint `json:"collateralPercentage"`
+
+	// The prices of the memory units and CPU steps used by Plutus scripts.
+	ExecutionUnitPrices ExecutionUnitPrices `json:"executionUnitPrices"`
+
+	// The maximum number of collateral inputs.
+	MaxCollateralInputs uint `json:"maxCollateralInputs"`
+
+	// The deposit required to register a DRep (in lovelace).
+	DRepDeposit uint `json:"dRepDeposit"`
+
+	// The deposit required to submit a governance action proposal (in lovelace).
+	GovActionDeposit uint `json:"govActionDeposit"`
+
+	// The fee per byte of the first 25600 bytes of reference scripts used by a transaction (in lovelace).
+	// The fee per byte increases by a factor of 1.2 for every following 25600 bytes.
+	MinFeeRefScriptCostPerByte float64 `json:"minFeeRefScriptCostPerByte"`
 }
 
 // LOadProtocol returns a pointer to a unmarshalled Protocol given a file path of a
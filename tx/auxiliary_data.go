@@ -1,6 +1,7 @@
 package tx
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
@@ -16,7 +17,9 @@ type AuxiliaryData struct {
 	Metadata           Metadata    `cbor:"0,keyasint,omitempty"`
 	NativeScripts      interface{} `cbor:"1,keyasint,omitempty"`
 	PlutusScripts      interface{} `cbor:"2,keyasint,omitempty"`
-	PreferAlonzoFormat bool        `cbor:"2,keyasint,omitempty"`
+	PreferAlonzoFormat bool        `cbor:"-"`
+
+	raw rawBytes
 }
 
 func NewAuxiliaryData() *AuxiliaryData {
@@ -67,11 +70,66 @@ func (d *AuxiliaryData) MarshalCBOR() ([]byte, error) {
 		return nil, err
 	}
 
-	return em.Marshal(auxiliaryData(*d))
+	enc, err := em.Marshal(auxiliaryData(*d))
+	if err != nil {
+		return nil, err
+	}
+	return d.raw.bytes(enc), nil
 }
 
-// UnmarshalCBOR implements cbor.Unmarshaler
+// UnmarshalCBOR implements cbor.Unmarshaler.
+// The original bytes are kept and returned by MarshalCBOR as long as the data is not modified.
 func (d *AuxiliaryData) UnmarshalCBOR(data []byte) error {
+	if err := d.unmarshalCBOR(data); err != nil {
+		return err
+	}
+
+	d.raw = rawBytes{}
+	enc, err := d.MarshalCBOR()
+	if err != nil {
+		return err
+	}
+	d.raw.keep(data, enc)
+
+	return nil
+}
+
+// unmarshalCBOR accepts the Shelley (metadata map), Shelley-MA ([metadata, scripts]) and
+// Alonzo (tag 259 map) formats of the auxiliary data.
+func (d *AuxiliaryData) unmarshalCBOR(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("cbor: cannot unmarshal empty data into AuxiliaryData")
+	}
+
+	switch data[0] >> 5 {
+	// Shelley: metadata only
+	case cborMajorTypeMap:
+		var metadata Metadata
+		if err := cborDec.Unmarshal(data, &metadata); err != nil {
+			return err
+		}
+		d.Metadata = metadata
+		d.PreferAlonzoFormat = false
+
+		return nil
+
+	// Shelley-MA: [metadata, [native scripts]]
+	case cborMajorTypeArray:
+		var aux struct {
+			_             struct{} `cbor:",toarray"`
+			Metadata      Metadata
+			NativeScripts []NativeScript
+		}
+		if err := cborDec.Unmarshal(data, &aux); err != nil {
+			return err
+		}
+		d.Metadata = aux.Metadata
+		d.NativeScripts = aux.NativeScripts
+		d.PreferAlonzoFormat = false
+
+		return nil
+	}
+
 	type auxiliaryData AuxiliaryData
 
 	// Register tag 259 for maps
@@ -92,6 +150,9 @@ func (d *AuxiliaryData) UnmarshalCBOR(data []byte) error {
 		return err
 	}
 	d.Metadata = dd.Metadata
+	d.NativeScripts = dd.NativeScripts
+	d.PlutusScripts = dd.PlutusScripts
+	d.PreferAlonzoFormat = true
 
 	return nil
 }
@@ -0,0 +1,139 @@
+package tx_test
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/fees"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBootstrapWitness(t *testing.T) {
+	// Icarus style Byron addresses use the BIP44 purpose
+	byronKey := createRootKey().Derive(harden(44)).Derive(harden(1815)).Derive(harden(0)).Derive(0).Derive(0)
+	byronAddr, err := address.NewByronAddress(byronKey.Public().PublicKey(), byronKey.ChainCode(), address.ByronAddressAttributes{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeAddr, _, err := generateBaseAddress(network.MainNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Byron addresses round trip as transaction outputs
+	output := tx.NewTxOutput(byronAddr, 1000000)
+	data, err := output.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decodedOutput tx.TxOutput
+	if err := decodedOutput.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, byronAddr.String(), decodedOutput.Address.String())
+
+	pr := loadTestProtocol(t)
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(byronAddr, 10000000),
+	))
+	builder.AddOutputs(tx.NewTxOutput(changeAddr, 2000000))
+	if err := builder.AddChangeIfNeeded(changeAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = builder.Build()
+	assert.ErrorIs(t, err, tx.ErrMissingWitness)
+
+	builder.Sign(byronKey)
+	txFinal, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, txFinal.WitnessSet.Witnesses)
+	if assert.Len(t, txFinal.WitnessSet.BootstrapWitnesses, 1) {
+		witness := txFinal.WitnessSet.BootstrapWitnesses[0]
+		hash, err := txFinal.Hash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, ed25519.Verify(witness.VKey, hash[:], witness.Signature))
+
+		root, err := address.NewByronAddress(witness.VKey, witness.ChainCode, address.ByronAddressAttributes{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, byronAddr.Hash, root.Hash)
+		assert.Equal(t, []byte{0xa0}, witness.Attributes)
+
+		keyHash, err := witness.KeyHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, tx.AddrKeyHash(byronAddr.Hash), keyHash)
+	}
+
+	// the fee estimated before signing covers the bootstrap witness
+	fee, err := txFinal.Fee(fees.NewLinearFee(pr.TxFeePerByte, pr.TxFeeFixed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.LessOrEqual(t, fee, uint(txFinal.Body.Fee))
+
+	data, err = txFinal.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, txFinal.WitnessSet.BootstrapWitnesses, decoded.WitnessSet.BootstrapWitnesses)
+}
+
+func TestBootstrapWitnessSignsNativeScript(t *testing.T) {
+	byronKey := createRootKey().Derive(harden(44)).Derive(harden(1815)).Derive(harden(0)).Derive(0).Derive(0)
+	byronAddr, err := address.NewByronAddress(byronKey.Public().PublicKey(), byronKey.ChainCode(), address.ByronAddressAttributes{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeAddr, _, err := generateBaseAddress(network.MainNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the key hash of a bootstrap witness is the root of its Byron address
+	policy, err := tx.NewScriptPubKey(byronAddr.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pr := loadTestProtocol(t)
+	utxos := []*tx.TxInput{tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(byronAddr, 10000000),
+	)}
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{byronKey})
+	builder.AddInputs(utxos...)
+	if err := builder.Mint(policy, tx.NewMintAsset("token", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.AddChangeIfNeeded(changeAddr); err != nil {
+		t.Fatal(err)
+	}
+	txFinal, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, txFinal.WitnessSet.Witnesses)
+	assert.Len(t, txFinal.WitnessSet.BootstrapWitnesses, 1)
+
+	violations, err := tx.Validate(&txFinal, utxos, pr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,273 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
+	"github.com/milos-ethernal/go-cardano-serialization/protocol"
+)
+
+type CertificateType uint64
+
+const (
+	CertStakeRegistration CertificateType = iota
+	CertStakeDeregistration
+	CertStakeDelegation
+	CertPoolRegistration
+	CertPoolRetirement
+	CertGenesisKeyDelegation
+	CertMoveInstantaneousRewards
+	// Conway stake registration and deregistration with explicit deposit
+	CertRegistration
+	CertUnregistration
+	// Conway vote delegation, combined registration and delegation, committee and DRep certificates
+	CertVoteDelegation
+	CertStakeVoteDelegation
+	CertStakeRegistrationDelegation
+	CertVoteRegistrationDelegation
+	CertStakeVoteRegistrationDelegation
+	CertCommitteeHotAuth
+	CertCommitteeColdResign
+	CertDRepRegistration
+	CertDRepUnregistration
+	CertDRepUpdate
+)
+
+// Certificate is a transaction certificate.
+// Certificate types that cannot be built with this package are kept in their decoded form.
+type Certificate struct {
+	Type CertificateType
+	// StakeCredential is the stake credential, or the cold credential of committee certificates
+	// and the DRep credential of DRep certificates.
+	StakeCredential *address.StakeCredential
+	PoolKeyHash     Hash28
+	DRep            DRep
+	// HotCredential is the committee hot credential authorized by the cold credential.
+	HotCredential *address.StakeCredential
+	// Deposit is the deposit paid by registration or refunded by deregistration certificates.
+	Deposit uint
+	Anchor  *Anchor
+
+	raw rawBytes
+}
+
+// NewStakeRegistrationCertificate returns a certificate registering the stake credential.
+// The key deposit from the protocol parameters is taken from the transaction.
+func NewStakeRegistrationCertificate(stake *address.StakeCredential) Certificate {
+	return Certificate{Type: CertStakeRegistration, StakeCredential: stake}
+}
+
+// NewStakeDeregistrationCertificate returns a certificate deregistering the stake credential.
+// The key deposit from the protocol parameters is refunded to the transaction.
+func NewStakeDeregistrationCertificate(stake *address.StakeCredential) Certificate {
+	return Certificate{Type: CertStakeDeregistration, StakeCredential: stake}
+}
+
+// NewStakeDelegationCertificate returns a certificate delegating the stake credential to the pool.
+func NewStakeDelegationCertificate(stake *address.StakeCredential, poolKeyHash []byte) Certificate {
+	return Certificate{Type: CertStakeDelegation, StakeCredential: stake, PoolKeyHash: poolKeyHash}
+}
+
+// The Conway certificates below carry their deposit. A zero deposit is set from the protocol parameters
+// when the certificate is added with TxBuilder.AddCertificates.
+
+// NewRegistrationCertificate returns a Conway certificate registering the stake credential with an explicit deposit.
+func NewRegistrationCertificate(stake *address.StakeCredential, deposit uint) Certificate {
+	return Certificate{Type: CertRegistration, StakeCredential: stake, Deposit: deposit}
+}
+
+// NewUnregistrationCertificate returns a Conway certificate deregistering the stake credential with an explicit refund.
+func NewUnregistrationCertificate(stake *address.StakeCredential, refund uint) Certificate {
+	return Certificate{Type: CertUnregistration, StakeCredential: stake, Deposit: refund}
+}
+
+// NewVoteDelegationCertificate returns a certificate delegating the votes of the stake credential to the DRep.
+func NewVoteDelegationCertificate(stake *address.StakeCredential, drep DRep) Certificate {
+	return Certificate{Type: CertVoteDelegation, StakeCredential: stake, DRep: drep}
+}
+
+// NewStakeVoteDelegationCertificate returns a certificate delegating the stake credential to the pool
+// and its votes to the DRep.
+func NewStakeVoteDelegationCertificate(stake *address.StakeCredential, poolKeyHash []byte, drep DRep) Certificate {
+	return Certificate{Type: CertStakeVoteDelegation, StakeCredential: stake, PoolKeyHash: poolKeyHash, DRep: drep}
+}
+
+// NewStakeRegistrationDelegationCertificate returns a certificate registering the stake credential
+// and delegating it to the pool.
+func NewStakeRegistrationDelegationCertificate(stake *address.StakeCredential, poolKeyHash []byte, deposit uint) Certificate {
+	return Certificate{Type: CertStakeRegistrationDelegation, StakeCredential: stake, PoolKeyHash: poolKeyHash, Deposit: deposit}
+}
+
+// NewVoteRegistrationDelegationCertificate returns a certificate registering the stake credential
+// and delegating its votes to the DRep.
+func NewVoteRegistrationDelegationCertificate(stake *address.StakeCredential, drep DRep, deposit uint) Certificate {
+	return Certificate{Type: CertVoteRegistrationDelegation, StakeCredential: stake, DRep: drep, Deposit: deposit}
+}
+
+// NewStakeVoteRegistrationDelegationCertificate returns a certificate registering the stake credential,
+// delegating it to the pool and its votes to the DRep.
+func NewStakeVoteRegistrationDelegationCertificate(stake *address.StakeCredential, poolKeyHash []byte, drep DRep, deposit uint) Certificate {
+	return Certificate{
+		Type:            CertStakeVoteRegistrationDelegation,
+		StakeCredential: stake,
+		PoolKeyHash:     poolKeyHash,
+		DRep:            drep,
+		Deposit:         deposit,
+	}
+}
+
+// NewCommitteeHotAuthCertificate returns a certificate authorizing the hot credential to vote
+// for the constitutional committee member with the cold credential.
+func NewCommitteeHotAuthCertificate(cold, hot *address.StakeCredential) Certificate {
+	return Certificate{Type: CertCommitteeHotAuth, StakeCredential: cold, HotCredential: hot}
+}
+
+// NewCommitteeColdResignCertificate returns a certificate resigning the constitutional committee member
+// with the cold credential, with an optional anchor to the rationale.
+func NewCommitteeColdResignCertificate(cold *address.StakeCredential, anchor *Anchor) Certificate {
+	return Certificate{Type: CertCommitteeColdResign, StakeCredential: cold, Anchor: anchor}
+}
+
+// NewDRepRegistrationCertificate returns a certificate registering the DRep credential,
+// with an optional anchor to the DRep metadata.
+func NewDRepRegistrationCertificate(drep *address.StakeCredential, deposit uint, anchor *Anchor) Certificate {
+	return Certificate{Type: CertDRepRegistration, StakeCredential: drep, Deposit: deposit, Anchor: anchor}
+}
+
+// NewDRepUnregistrationCertificate returns a certificate retiring the DRep credential.
+func NewDRepUnregistrationCertificate(drep *address.StakeCredential, refund uint) Certificate {
+	return Certificate{Type: CertDRepUnregistration, StakeCredential: drep, Deposit: refund}
+}
+
+// NewDRepUpdateCertificate returns a certificate updating the metadata anchor of the DRep credential.
+func NewDRepUpdateCertificate(drep *address.StakeCredential, anchor *Anchor) Certificate {
+	return Certificate{Type: CertDRepUpdate, StakeCredential: drep, Anchor: anchor}
+}
+
+// setDeposit sets a zero deposit or refund of the certificate from the protocol parameters.
+func (c *Certificate) setDeposit(pr protocol.Protocol) {
+	if c.Deposit != 0 {
+		return
+	}
+	switch c.Type {
+	case CertRegistration, CertUnregistration, CertStakeRegistrationDelegation,
+		CertVoteRegistrationDelegation, CertStakeVoteRegistrationDelegation:
+		c.Deposit = pr.StakeAddressDeposit
+	case CertDRepRegistration, CertDRepUnregistration:
+		c.Deposit = pr.DRepDeposit
+	}
+}
+
+// deposits returns the deposit paid and refunded by the transaction for the certificate.
+func (c *Certificate) deposits(pr protocol.Protocol) (deposit, refund uint) {
+	switch c.Type {
+	case CertStakeRegistration:
+		return pr.StakeAddressDeposit, 0
+	case CertStakeDeregistration:
+		return 0, pr.StakeAddressDeposit
+	case CertRegistration, CertStakeRegistrationDelegation, CertVoteRegistrationDelegation,
+		CertStakeVoteRegistrationDelegation, CertDRepRegistration:
+		return c.Deposit, 0
+	case CertUnregistration, CertDRepUnregistration:
+		return 0, c.Deposit
+	}
+	return 0, 0
+}
+
+// witnessCredential returns the credential that has to witness the certificate, or nil if none is required.
+func (c *Certificate) witnessCredential() *address.StakeCredential {
+	switch c.Type {
+	case CertStakeRegistration, CertPoolRegistration, CertPoolRetirement, CertGenesisKeyDelegation,
+		CertMoveInstantaneousRewards:
+		return nil
+	}
+	return c.StakeCredential
+}
+
+// fields returns pointers to the fields of the certificate in their encoding order after the type,
+// or nil if the type is not supported.
+func (c *Certificate) fields() []interface{} {
+	switch c.Type {
+	case CertStakeRegistration, CertStakeDeregistration:
+		return []interface{}{&c.StakeCredential}
+	case CertStakeDelegation:
+		return []interface{}{&c.StakeCredential, &c.PoolKeyHash}
+	case CertRegistration, CertUnregistration:
+		return []interface{}{&c.StakeCredential, &c.Deposit}
+	case CertVoteDelegation:
+		return []interface{}{&c.StakeCredential, &c.DRep}
+	case CertStakeVoteDelegation:
+		return []interface{}{&c.StakeCredential, &c.PoolKeyHash, &c.DRep}
+	case CertStakeRegistrationDelegation:
+		return []interface{}{&c.StakeCredential, &c.PoolKeyHash, &c.Deposit}
+	case CertVoteRegistrationDelegation:
+		return []interface{}{&c.StakeCredential, &c.DRep, &c.Deposit}
+	case CertStakeVoteRegistrationDelegation:
+		return []interface{}{&c.StakeCredential, &c.PoolKeyHash, &c.DRep, &c.Deposit}
+	case CertCommitteeHotAuth:
+		return []interface{}{&c.StakeCredential, &c.HotCredential}
+	case CertCommitteeColdResign:
+		return []interface{}{&c.StakeCredential, &c.Anchor}
+	case CertDRepRegistration:
+		return []interface{}{&c.StakeCredential, &c.Deposit, &c.Anchor}
+	case CertDRepUnregistration:
+		return []interface{}{&c.StakeCredential, &c.Deposit}
+	case CertDRepUpdate:
+		return []interface{}{&c.StakeCredential, &c.Anchor}
+	}
+	return nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (c *Certificate) MarshalCBOR() ([]byte, error) {
+	fields := c.fields()
+	if fields == nil {
+		if c.raw.raw == nil {
+			return nil, fmt.Errorf("cbor: unsupported certificate type %d", c.Type)
+		}
+		return c.raw.raw, nil
+	}
+
+	enc, err := cborEnc.Marshal(append([]interface{}{c.Type}, fields...))
+	if err != nil {
+		return nil, err
+	}
+	return c.raw.bytes(enc), nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (c *Certificate) UnmarshalCBOR(data []byte) error {
+	certType, err := getTypeFromCBORArray(data)
+	if err != nil {
+		return fmt.Errorf("cbor: cannot unmarshal CBOR array into Certificate (%v)", err)
+	}
+
+	*c = Certificate{Type: CertificateType(certType)}
+	fields := c.fields()
+	if fields == nil {
+		c.raw.keep(data, nil)
+		return nil
+	}
+
+	var raw []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != len(fields)+1 {
+		return fmt.Errorf("cbor: certificate type %d has %d fields", c.Type, len(raw))
+	}
+	for i, field := range fields {
+		if err := cborDec.Unmarshal(raw[i+1], field); err != nil {
+			return err
+		}
+	}
+
+	enc, err := c.MarshalCBOR()
+	if err != nil {
+		return err
+	}
+	c.raw.keep(data, enc)
+
+	return nil
+}
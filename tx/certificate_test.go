@@ -0,0 +1,389 @@
+package tx_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/protocol"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func stakeKey() bip32.XPrv {
+	return createRootKey().Derive(harden(1852)).Derive(harden(1815)).Derive(harden(0)).Derive(2).Derive(0)
+}
+
+func TestCertificateCBOR(t *testing.T) {
+	keyHash, _ := hex.DecodeString("1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+	poolKeyHash, _ := hex.DecodeString("d8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1")
+	stake := address.NewKeyStakeCredential(keyHash)
+
+	for _, sc := range []struct {
+		description string
+		cert        tx.Certificate
+		hex         string
+	}{
+		{
+			description: "stake registration",
+			cert:        tx.NewStakeRegistrationCertificate(stake),
+			hex:         "82008200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209",
+		},
+		{
+			description: "stake delegation",
+			cert:        tx.NewStakeDelegationCertificate(stake, poolKeyHash),
+			hex: "83028200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209" +
+				"581cd8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1",
+		},
+		{
+			description: "conway stake registration",
+			cert:        tx.NewRegistrationCertificate(stake, 2000000),
+			hex:         "83078200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df2091a001e8480",
+		},
+		{
+			description: "vote delegation to always abstain",
+			cert:        tx.NewVoteDelegationCertificate(stake, tx.NewAlwaysAbstainDRep()),
+			hex:         "83098200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df2098102",
+		},
+		{
+			description: "stake vote delegation",
+			cert:        tx.NewStakeVoteDelegationCertificate(stake, poolKeyHash, tx.NewDRep(stake)),
+			hex: "840a8200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209" +
+				"581cd8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1" +
+				"8200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209",
+		},
+		{
+			description: "stake vote registration delegation",
+			cert:        tx.NewStakeVoteRegistrationDelegationCertificate(stake, poolKeyHash, tx.NewAlwaysNoConfidenceDRep(), 2000000),
+			hex: "850d8200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209" +
+				"581cd8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1" +
+				"81031a001e8480",
+		},
+		{
+			description: "committee hot key authorization",
+			cert:        tx.NewCommitteeHotAuthCertificate(stake, address.NewKeyStakeCredential(poolKeyHash)),
+			hex: "830e8200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209" +
+				"8200581cd8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1",
+		},
+		{
+			description: "committee resignation",
+			cert:        tx.NewCommitteeColdResignCertificate(stake, nil),
+			hex:         "830f8200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209f6",
+		},
+		{
+			description: "drep registration",
+			cert:        tx.NewDRepRegistrationCertificate(stake, 500000000, &tx.Anchor{URL: "https://a.b", DataHash: make([]byte, 32)}),
+			hex: "84108200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df2091a1dcd6500" +
+				"826b68747470733a2f2f612e62" + "5820" + strings.Repeat("00", 32),
+		},
+		{
+			description: "drep update",
+			cert:        tx.NewDRepUpdateCertificate(stake, nil),
+			hex:         "83128200581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209f6",
+		},
+	} {
+		t.Run(sc.description, func(t *testing.T) {
+			data, err := sc.cert.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, sc.hex, hex.EncodeToString(data))
+
+			decoded := tx.Certificate{}
+			if err := decoded.UnmarshalCBOR(data); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, sc.cert.Type, decoded.Type)
+			assert.Equal(t, sc.cert.StakeCredential, decoded.StakeCredential)
+			assert.Equal(t, sc.cert.Deposit, decoded.Deposit)
+			assert.Equal(t, sc.cert.DRep, decoded.DRep)
+			assert.Equal(t, sc.cert.Anchor, decoded.Anchor)
+		})
+	}
+}
+
+func TestStakeDelegationTx(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	poolKeyHash, _ := hex.DecodeString("d8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1")
+
+	pr := loadTestProtocol(t)
+	newBuilder := func(xprvs ...bip32.XPrv) *tx.TxBuilder {
+		builder := tx.NewTxBuilder(pr, xprvs)
+		builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+		builder.AddCertificates(
+			tx.NewStakeRegistrationCertificate(&addr.Stake),
+			tx.NewStakeDelegationCertificate(&addr.Stake, poolKeyHash),
+		)
+		if err := builder.AddChangeIfNeeded(addr); err != nil {
+			t.Fatal(err)
+		}
+		return builder
+	}
+
+	_, err = newBuilder(prv).Build()
+	assert.ErrorIs(t, err, tx.ErrMissingWitness)
+
+	txFinal, err := newBuilder(prv, stakeKey()).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, txFinal.WitnessSet.Witnesses, 2)
+	// The key deposit is paid from the inputs
+	assert.Equal(t, uint(10000000)-pr.StakeAddressDeposit-uint(txFinal.Body.Fee), txFinal.Body.Outputs[0].Amount)
+
+	data, err := txFinal.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, decoded.Body.Certificates, 2)
+	assert.Equal(t, tx.CertStakeDelegation, decoded.Body.Certificates[1].Type)
+	assert.Equal(t, tx.Hash28(poolKeyHash), decoded.Body.Certificates[1].PoolKeyHash)
+}
+
+func TestRewardWithdrawalTx(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr := addr.ToReward()
+
+	newBuilder := func(xprvs ...bip32.XPrv) *tx.TxBuilder {
+		builder := tx.NewTxBuilder(loadTestProtocol(t), xprvs)
+		builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+		builder.AddWithdrawal(rewardAddr, 3000000)
+		if err := builder.AddChangeIfNeeded(addr); err != nil {
+			t.Fatal(err)
+		}
+		return builder
+	}
+
+	_, err = newBuilder(prv).Build()
+	assert.ErrorIs(t, err, tx.ErrMissingWitness)
+
+	txFinal, err := newBuilder(prv, stakeKey()).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The withdrawn rewards are added to the change
+	assert.Equal(t, uint(13000000)-uint(txFinal.Body.Fee), txFinal.Body.Outputs[0].Amount)
+
+	data, err := txFinal.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tx.Withdrawals{tx.NewRewardAccount(rewardAddr): 3000000}, decoded.Body.Withdrawals)
+
+	account, err := tx.NewRewardAccount(rewardAddr).Address()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rewardAddr.String(), account.String())
+
+	// A withdrawal from an account that is not a reward address cannot be witnessed
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{prv, stakeKey()})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+	builder.AddOutputs(tx.NewTxOutput(addr, 2000000))
+	builder.Tx().Body.Withdrawals = tx.Withdrawals{tx.RewardAccount(addr.Bytes()): 3000000}
+	_, err = builder.MinFee()
+	assert.Error(t, err)
+	_, err = builder.Build()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid withdrawal reward account")
+	}
+}
+
+func TestScriptWithdrawalRequiresScript(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyHash := prv.Public().PublicKey().Hash()
+	script, err := tx.NewScriptPubKey(keyHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptHash, err := script.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr := address.NewRewardAddress(network.TestNet(), address.NewScriptStakeCredential(scriptHash))
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000),
+	))
+	builder.AddWithdrawal(rewardAddr, 1000000)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both the missing signing key and the missing script are reported
+	_, err = builder.Build()
+	var missing *tx.MissingSignersError
+	if assert.ErrorAs(t, err, &missing) {
+		assert.ErrorIs(t, err, tx.ErrMissingWitness)
+		assert.Equal(t, []tx.Hash28{keyHash[:]}, missing.KeyHashes)
+		assert.Equal(t, []tx.Hash28{scriptHash}, missing.ScriptHashes)
+		assert.Contains(t, err.Error(), hex.EncodeToString(scriptHash))
+	}
+
+	builder.Sign(prv)
+	if err := builder.AddNativeScripts(script); err != nil {
+		t.Fatal(err)
+	}
+	_, err = builder.Build()
+	assert.NoError(t, err)
+}
+
+func TestPlutusScriptWithdrawal(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := tx.NewPlutusScript(tx.PlutusV2, []byte{0x4e, 0x4d, 0x01, 0x00, 0x00})
+	scriptHash, err := script.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewardAddr := address.NewRewardAddress(network.TestNet(), address.NewScriptStakeCredential(scriptHash))
+	pr := loadTestProtocol(t)
+	pr.CostModels = protocol.CostModels{"PlutusV2": {1, 2, 3}}
+
+	newBuilder := func() *tx.TxBuilder {
+		builder := tx.NewTxBuilder(pr, []bip32.XPrv{prv})
+		builder.AddInputs(tx.NewTxInputFromOutput(
+			"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000),
+		))
+		builder.AddWithdrawal(rewardAddr, 1000000)
+		builder.AddRedeemers(tx.NewRedeemer(tx.RedeemerTagReward, 0, tx.NewConstrPlutusData(0), tx.ExUnits{Mem: 1000, Steps: 2000}))
+		return builder
+	}
+
+	// The script is included in the witness set
+	builder := newBuilder()
+	builder.AddPlutusScripts(script)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	_, err = builder.Build()
+	assert.NoError(t, err)
+
+	// The script is referenced by a reference input
+	refOutput := tx.NewTxOutput(addr, 2000000)
+	refOutput.ScriptRef = tx.NewPlutusScriptRef(script)
+	builder = newBuilder()
+	builder.AddReferenceInputs(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, refOutput,
+	))
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	_, err = builder.Build()
+	assert.NoError(t, err)
+
+	// Without the script
+	builder = newBuilder()
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	_, err = builder.Build()
+	assert.ErrorIs(t, err, tx.ErrMissingWitness)
+}
+
+func TestRequiredSigners(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymentHash := prv.Public().PublicKey().Hash()
+	otherHash := stakeKey().Public().PublicKey().Hash()
+	signerHash, _ := hex.DecodeString("1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000),
+	))
+	builder.AddRequiredSigners(signerHash, otherHash[:], signerHash)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, builder.Tx().Body.RequiredSigners, 2)
+
+	_, err = builder.Build()
+	var missing *tx.MissingSignersError
+	if assert.ErrorAs(t, err, &missing) {
+		assert.ErrorIs(t, err, tx.ErrMissingWitness)
+		assert.ElementsMatch(t, []tx.Hash28{signerHash, otherHash[:], paymentHash[:]}, missing.KeyHashes)
+		assert.True(t, sort.SliceIsSorted(missing.KeyHashes, func(i, j int) bool {
+			return bytes.Compare(missing.KeyHashes[i], missing.KeyHashes[j]) < 0
+		}))
+	}
+
+	builder.Sign(prv)
+	builder.Sign(stakeKey())
+	_, err = builder.Build()
+	if assert.ErrorAs(t, err, &missing) {
+		assert.Equal(t, []tx.Hash28{signerHash}, missing.KeyHashes)
+		assert.Contains(t, err.Error(), "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+	}
+
+	data, err := builder.Tx().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, builder.Tx().Body.RequiredSigners, decoded.Body.RequiredSigners)
+}
+
+func TestDRepRegistrationTx(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	drepHash := stakeKey().Public().PublicKey().Hash()
+	drep := address.NewKeyStakeCredential(drepHash[:])
+
+	pr := loadTestProtocol(t)
+	pr.DRepDeposit = 500000000
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{prv})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 600000000))
+	builder.AddCertificates(
+		tx.NewDRepRegistrationCertificate(drep, 0, nil),
+		tx.NewVoteRegistrationDelegationCertificate(drep, tx.NewDRep(drep), 0),
+	)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	body := builder.Tx().Body
+	assert.Equal(t, uint(500000000), body.Certificates[0].Deposit)
+	assert.Equal(t, pr.StakeAddressDeposit, body.Certificates[1].Deposit)
+	assert.Equal(t, uint(600000000-500000000)-pr.StakeAddressDeposit-uint(body.Fee), body.Outputs[0].Amount)
+
+	_, err = builder.Build()
+	assert.ErrorIs(t, err, tx.ErrMissingWitness)
+
+	builder.Sign(stakeKey())
+	_, err = builder.Build()
+	assert.NoError(t, err)
+}
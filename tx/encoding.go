@@ -1,18 +1,67 @@
 package tx
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 
 	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
 )
 
+// CBOR major types as found in the high 3 bits of the initial byte of a data item.
+const (
+	cborMajorTypeArray = 4
+	cborMajorTypeMap   = 5
+)
+
 var cborEnc, _ = cbor.CanonicalEncOptions().EncMode()
 var cborDec, _ = cbor.DecOptions{MapKeyByteString: cbor.MapKeyByteStringWrap}.DecMode()
 
 func GetCBOR_EncMode() cbor.EncMode { return cborEnc }
 func GetCBOR_DecMode() cbor.DecMode { return cborDec }
 
+// rawBytes keeps the bytes a value was decoded from, so that values received
+// from other tools re-encode (and hash) exactly as they were received.
+type rawBytes struct {
+	// raw is the original encoding of the value.
+	raw []byte
+	// enc is the encoding produced by this package right after decoding,
+	// used to detect whether the value has been mutated since.
+	enc []byte
+}
+
+// keep stores the original encoding data along with the current encoding enc of the value.
+func (r *rawBytes) keep(data, enc []byte) {
+	r.raw = append([]byte{}, data...)
+	r.enc = enc
+}
+
+// bytes returns the original encoding if the value still encodes to enc, otherwise enc.
+func (r *rawBytes) bytes(enc []byte) []byte {
+	if r.raw != nil && bytes.Equal(r.enc, enc) {
+		return r.raw
+	}
+	return enc
+}
+
+// wrapCBOR returns data embedded as a tag 24 (encoded CBOR data item) byte string.
+func wrapCBOR(data []byte) ([]byte, error) {
+	return cborEnc.Marshal(cbor.Tag{Number: 24, Content: data})
+}
+
+// unwrapCBOR returns the content of a tag 24 (encoded CBOR data item) byte string.
+func unwrapCBOR(data []byte) ([]byte, error) {
+	var tag cbor.Tag
+	if err := cborDec.Unmarshal(data, &tag); err != nil {
+		return nil, err
+	}
+	content, ok := tag.Content.([]byte)
+	if tag.Number != 24 || !ok {
+		return nil, fmt.Errorf("cbor: expected tag 24 byte string, got tag %d", tag.Number)
+	}
+	return content, nil
+}
+
 func getTypeFromCBORArray(data []byte) (uint64, error) {
 	raw := []interface{}{}
 	if err := cborDec.Unmarshal(data, &raw); err != nil {
@@ -0,0 +1,394 @@
+package tx
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
+)
+
+// Anchor links off-chain content (e.g. the rationale of a vote) by URL and the blake2b256 hash of the content.
+type Anchor struct {
+	_        struct{} `cbor:",toarray"`
+	URL      string
+	DataHash []byte
+}
+
+// NewAnchor returns an Anchor.
+func NewAnchor(url string, dataHash []byte) Anchor {
+	return Anchor{URL: url, DataHash: dataHash}
+}
+
+// GovActionID identifies a governance action by the transaction proposing it and its index in the proposals.
+type GovActionID struct {
+	_      struct{} `cbor:",toarray"`
+	TxHash [32]byte
+	Index  uint16
+}
+
+// NewGovActionID returns the GovActionID of the index-th proposal of the transaction.
+func NewGovActionID(txHash []byte, index uint16) GovActionID {
+	id := GovActionID{Index: index}
+	copy(id.TxHash[:], txHash)
+
+	return id
+}
+
+type VoterType uint64
+
+const (
+	VoterCommitteeHotKey VoterType = iota
+	VoterCommitteeHotScript
+	VoterDRepKey
+	VoterDRepScript
+	VoterStakePool
+)
+
+// Voter is a constitutional committee member, DRep or stake pool operator, identified by a key or script hash.
+type Voter struct {
+	_    struct{} `cbor:",toarray"`
+	Type VoterType
+	Hash [28]byte
+}
+
+// NewVoter returns a Voter of the type with the key or script hash.
+func NewVoter(voterType VoterType, hash []byte) Voter {
+	voter := Voter{Type: voterType}
+	copy(voter.Hash[:], hash)
+
+	return voter
+}
+
+// credential returns the credential that has to witness the votes of the voter.
+func (v Voter) credential() *address.StakeCredential {
+	switch v.Type {
+	case VoterCommitteeHotScript, VoterDRepScript:
+		return address.NewScriptStakeCredential(v.Hash[:])
+	}
+	return address.NewKeyStakeCredential(v.Hash[:])
+}
+
+type Vote uint64
+
+const (
+	VoteNo Vote = iota
+	VoteYes
+	VoteAbstain
+)
+
+// VotingProcedure is a vote on a governance action, with an optional anchor to its rationale.
+type VotingProcedure struct {
+	_      struct{} `cbor:",toarray"`
+	Vote   Vote
+	Anchor *Anchor
+}
+
+// VotingProcedures maps voters to their votes on governance actions.
+type VotingProcedures map[Voter]map[GovActionID]VotingProcedure
+
+type GovActionType uint64
+
+const (
+	GovActionParameterChange GovActionType = iota
+	GovActionHardForkInitiation
+	GovActionTreasuryWithdrawals
+	GovActionNoConfidence
+	GovActionUpdateCommittee
+	GovActionNewConstitution
+	GovActionInfo
+)
+
+// ProtocolVersion is the protocol version a hard fork initiation moves to.
+type ProtocolVersion struct {
+	_     struct{} `cbor:",toarray"`
+	Major uint64
+	Minor uint64
+}
+
+// UnitInterval is a rational number between 0 and 1.
+type UnitInterval struct {
+	Numerator   uint64
+	Denominator uint64
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (u UnitInterval) MarshalCBOR() ([]byte, error) {
+	return cborEnc.Marshal(cbor.Tag{Number: 30, Content: []uint64{u.Numerator, u.Denominator}})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (u *UnitInterval) UnmarshalCBOR(data []byte) error {
+	var tag cbor.RawTag
+	if err := cborDec.Unmarshal(data, &tag); err != nil {
+		return err
+	}
+	var fraction []uint64
+	if err := cborDec.Unmarshal(tag.Content, &fraction); err != nil {
+		return err
+	}
+	if tag.Number != 30 || len(fraction) != 2 {
+		return fmt.Errorf("cbor: invalid unit interval")
+	}
+	u.Numerator, u.Denominator = fraction[0], fraction[1]
+
+	return nil
+}
+
+// CommitteeMember is a constitutional committee cold credential with the epoch its term ends.
+type CommitteeMember struct {
+	ColdCredential *address.StakeCredential
+	Epoch          uint64
+}
+
+// Constitution is the anchor of the constitution text and the hash of its guardrail script.
+type Constitution struct {
+	_          struct{} `cbor:",toarray"`
+	Anchor     Anchor
+	ScriptHash Hash28
+}
+
+// GovAction is a governance action.
+// Only the fields of its Type are used; PrevActionID is the last enacted action of the same purpose,
+// nil for the first one.
+type GovAction struct {
+	Type         GovActionType
+	PrevActionID *GovActionID
+
+	// GovActionParameterChange: the protocol parameters to update, by their protocol_param_update key.
+	ParamUpdate map[uint64]interface{}
+	// GovActionParameterChange and GovActionTreasuryWithdrawals: the hash of the guardrail script.
+	PolicyHash Hash28
+	// GovActionHardForkInitiation
+	ProtocolVersion ProtocolVersion
+	// GovActionTreasuryWithdrawals
+	Withdrawals Withdrawals
+	// GovActionUpdateCommittee
+	RemovedMembers []*address.StakeCredential
+	AddedMembers   []CommitteeMember
+	Quorum         UnitInterval
+	// GovActionNewConstitution
+	Constitution Constitution
+}
+
+// NewParameterChangeAction returns an action updating protocol parameters.
+func NewParameterChangeAction(prev *GovActionID, update map[uint64]interface{}, policyHash Hash28) GovAction {
+	return GovAction{Type: GovActionParameterChange, PrevActionID: prev, ParamUpdate: update, PolicyHash: policyHash}
+}
+
+// NewHardForkInitiationAction returns an action moving the chain to the protocol version.
+func NewHardForkInitiationAction(prev *GovActionID, major, minor uint64) GovAction {
+	return GovAction{Type: GovActionHardForkInitiation, PrevActionID: prev, ProtocolVersion: ProtocolVersion{Major: major, Minor: minor}}
+}
+
+// NewTreasuryWithdrawalsAction returns an action withdrawing lovelace from the treasury to reward accounts.
+func NewTreasuryWithdrawalsAction(withdrawals Withdrawals, policyHash Hash28) GovAction {
+	return GovAction{Type: GovActionTreasuryWithdrawals, Withdrawals: withdrawals, PolicyHash: policyHash}
+}
+
+// NewNoConfidenceAction returns an action stating no confidence in the constitutional committee.
+func NewNoConfidenceAction(prev *GovActionID) GovAction {
+	return GovAction{Type: GovActionNoConfidence, PrevActionID: prev}
+}
+
+// NewUpdateCommitteeAction returns an action removing and adding constitutional committee members
+// and setting the quorum.
+func NewUpdateCommitteeAction(prev *GovActionID, removed []*address.StakeCredential, added []CommitteeMember, quorum UnitInterval) GovAction {
+	return GovAction{Type: GovActionUpdateCommittee, PrevActionID: prev, RemovedMembers: removed, AddedMembers: added, Quorum: quorum}
+}
+
+// NewConstitutionAction returns an action replacing the constitution.
+func NewConstitutionAction(prev *GovActionID, constitution Constitution) GovAction {
+	return GovAction{Type: GovActionNewConstitution, PrevActionID: prev, Constitution: constitution}
+}
+
+// NewInfoAction returns an info action, which has no effect on chain.
+func NewInfoAction() GovAction {
+	return GovAction{Type: GovActionInfo}
+}
+
+// credentialKey is a stake credential usable as a map key.
+type credentialKey struct {
+	_    struct{} `cbor:",toarray"`
+	Kind address.StakeCredentialType
+	Hash [28]byte
+}
+
+func newCredentialKey(cred *address.StakeCredential) credentialKey {
+	key := credentialKey{Kind: cred.Kind}
+	copy(key.Hash[:], cred.Payload)
+
+	return key
+}
+
+// nullable returns nil for an empty hash, which is encoded as null.
+func nullable(hash []byte) interface{} {
+	if len(hash) == 0 {
+		return nil
+	}
+	return hash
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (a GovAction) MarshalCBOR() ([]byte, error) {
+	var action []interface{}
+	switch a.Type {
+	case GovActionParameterChange:
+		action = []interface{}{a.Type, a.PrevActionID, a.ParamUpdate, nullable(a.PolicyHash)}
+	case GovActionHardForkInitiation:
+		action = []interface{}{a.Type, a.PrevActionID, a.ProtocolVersion}
+	case GovActionTreasuryWithdrawals:
+		action = []interface{}{a.Type, a.Withdrawals, nullable(a.PolicyHash)}
+	case GovActionNoConfidence:
+		action = []interface{}{a.Type, a.PrevActionID}
+	case GovActionUpdateCommittee:
+		added := map[credentialKey]uint64{}
+		for _, member := range a.AddedMembers {
+			added[newCredentialKey(member.ColdCredential)] = member.Epoch
+		}
+		action = []interface{}{a.Type, a.PrevActionID, a.RemovedMembers, added, a.Quorum}
+	case GovActionNewConstitution:
+		constitution := []interface{}{a.Constitution.Anchor, nullable(a.Constitution.ScriptHash)}
+		action = []interface{}{a.Type, a.PrevActionID, constitution}
+	case GovActionInfo:
+		action = []interface{}{a.Type}
+	default:
+		return nil, fmt.Errorf("cbor: unsupported governance action type %d", a.Type)
+	}
+
+	return cborEnc.Marshal(action)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (a *GovAction) UnmarshalCBOR(data []byte) error {
+	var fields []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("cbor: cannot unmarshal CBOR array into GovAction (%v)", err)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("cbor: cannot unmarshal empty CBOR array into GovAction")
+	}
+
+	*a = GovAction{}
+	if err := cborDec.Unmarshal(fields[0], &a.Type); err != nil {
+		return err
+	}
+
+	var targets []interface{}
+	added := map[credentialKey]uint64{}
+	switch a.Type {
+	case GovActionParameterChange:
+		targets = []interface{}{&a.PrevActionID, &a.ParamUpdate, &a.PolicyHash}
+	case GovActionHardForkInitiation:
+		targets = []interface{}{&a.PrevActionID, &a.ProtocolVersion}
+	case GovActionTreasuryWithdrawals:
+		targets = []interface{}{&a.Withdrawals, &a.PolicyHash}
+	case GovActionNoConfidence:
+		targets = []interface{}{&a.PrevActionID}
+	case GovActionUpdateCommittee:
+		targets = []interface{}{&a.PrevActionID, &a.RemovedMembers, &added, &a.Quorum}
+	case GovActionNewConstitution:
+		targets = []interface{}{&a.PrevActionID, &a.Constitution}
+	case GovActionInfo:
+	default:
+		return fmt.Errorf("cbor: unsupported governance action type %d", a.Type)
+	}
+
+	if len(fields) != len(targets)+1 {
+		return fmt.Errorf("cbor: governance action type %d has %d fields", a.Type, len(fields))
+	}
+	for i, target := range targets {
+		if err := cborDec.Unmarshal(fields[i+1], target); err != nil {
+			return err
+		}
+	}
+
+	for key, epoch := range added {
+		cred := &address.StakeCredential{Kind: key.Kind, Payload: append([]byte{}, key.Hash[:]...)}
+		a.AddedMembers = append(a.AddedMembers, CommitteeMember{ColdCredential: cred, Epoch: epoch})
+	}
+	sort.Slice(a.AddedMembers, func(i, j int) bool {
+		return bytes.Compare(a.AddedMembers[i].ColdCredential.Payload, a.AddedMembers[j].ColdCredential.Payload) < 0
+	})
+
+	return nil
+}
+
+// ProposalProcedure proposes a governance action. The deposit is returned to the reward account
+// once the action is enacted or expires.
+type ProposalProcedure struct {
+	_             struct{} `cbor:",toarray"`
+	Deposit       uint
+	RewardAccount RewardAccount
+	GovAction     GovAction
+	Anchor        Anchor
+}
+
+type DRepType uint64
+
+const (
+	DRepKeyHash DRepType = iota
+	DRepScriptHash
+	DRepAlwaysAbstain
+	DRepAlwaysNoConfidence
+)
+
+// DRep is the delegated representative stake votes are delegated to: a key or script hash,
+// or one of the predefined always abstain and always no confidence options.
+type DRep struct {
+	Type DRepType
+	Hash Hash28
+}
+
+// NewDRep returns the DRep with the credential.
+func NewDRep(cred *address.StakeCredential) DRep {
+	if cred.Kind == address.ScriptStakeCredentialType {
+		return DRep{Type: DRepScriptHash, Hash: cred.Payload}
+	}
+	return DRep{Type: DRepKeyHash, Hash: cred.Payload}
+}
+
+// NewAlwaysAbstainDRep returns the predefined DRep abstaining from every vote.
+func NewAlwaysAbstainDRep() DRep {
+	return DRep{Type: DRepAlwaysAbstain}
+}
+
+// NewAlwaysNoConfidenceDRep returns the predefined DRep voting no confidence on every vote.
+func NewAlwaysNoConfidenceDRep() DRep {
+	return DRep{Type: DRepAlwaysNoConfidence}
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (d DRep) MarshalCBOR() ([]byte, error) {
+	switch d.Type {
+	case DRepKeyHash, DRepScriptHash:
+		return cborEnc.Marshal([]interface{}{d.Type, d.Hash})
+	case DRepAlwaysAbstain, DRepAlwaysNoConfidence:
+		return cborEnc.Marshal([]interface{}{d.Type})
+	}
+	return nil, fmt.Errorf("cbor: unsupported DRep type %d", d.Type)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (d *DRep) UnmarshalCBOR(data []byte) error {
+	var fields []cbor.RawMessage
+	if err := cborDec.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("cbor: cannot unmarshal empty CBOR array into DRep")
+	}
+
+	*d = DRep{}
+	if err := cborDec.Unmarshal(fields[0], &d.Type); err != nil {
+		return err
+	}
+	switch {
+	case (d.Type == DRepKeyHash || d.Type == DRepScriptHash) && len(fields) == 2:
+		return cborDec.Unmarshal(fields[1], &d.Hash)
+	case (d.Type == DRepAlwaysAbstain || d.Type == DRepAlwaysNoConfidence) && len(fields) == 1:
+		return nil
+	}
+	return fmt.Errorf("cbor: invalid DRep of type %d with %d fields", d.Type, len(fields))
+}
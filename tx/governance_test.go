@@ -0,0 +1,178 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGovActionCBOR(t *testing.T) {
+	keyHash, _ := hex.DecodeString("1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+	txHash, _ := hex.DecodeString("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380")
+	prev := tx.NewGovActionID(txHash, 1)
+	rewardAccount := tx.NewRewardAccount(address.NewRewardAddress(network.TestNet(), address.NewKeyStakeCredential(keyHash)))
+
+	for _, sc := range []struct {
+		description string
+		action      tx.GovAction
+		hex         string
+	}{
+		{
+			description: "info",
+			action:      tx.NewInfoAction(),
+			hex:         "8106",
+		},
+		{
+			description: "hard fork initiation",
+			action:      tx.NewHardForkInitiationAction(nil, 10, 0),
+			hex:         "8301f6820a00",
+		},
+		{
+			description: "no confidence",
+			action:      tx.NewNoConfidenceAction(&prev),
+			hex:         "820382" + "5820fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380" + "01",
+		},
+		{
+			description: "parameter change",
+			action:      tx.NewParameterChangeAction(nil, map[uint64]interface{}{0: uint64(44)}, nil),
+			hex:         "8400f6a100182cf6",
+		},
+		{
+			description: "treasury withdrawals",
+			action:      tx.NewTreasuryWithdrawalsAction(tx.Withdrawals{rewardAccount: 1000000}, nil),
+		},
+		{
+			description: "update committee",
+			action: tx.NewUpdateCommitteeAction(&prev,
+				[]*address.StakeCredential{address.NewScriptStakeCredential(keyHash)},
+				[]tx.CommitteeMember{{ColdCredential: address.NewKeyStakeCredential(keyHash), Epoch: 500}},
+				tx.UnitInterval{Numerator: 2, Denominator: 3},
+			),
+		},
+		{
+			description: "new constitution",
+			action: tx.NewConstitutionAction(&prev, tx.Constitution{
+				Anchor:     tx.NewAnchor("https://example.com/constitution.txt", make([]byte, 32)),
+				ScriptHash: keyHash,
+			}),
+		},
+	} {
+		t.Run(sc.description, func(t *testing.T) {
+			data, err := sc.action.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.hex != "" {
+				assert.Equal(t, sc.hex, hex.EncodeToString(data))
+			}
+
+			var decoded tx.GovAction
+			if err := decoded.UnmarshalCBOR(data); err != nil {
+				t.Fatal(err)
+			}
+			redata, err := decoded.MarshalCBOR()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, data, redata)
+			assert.Equal(t, sc.action.Type, decoded.Type)
+			assert.Equal(t, sc.action.PrevActionID, decoded.PrevActionID)
+		})
+	}
+}
+
+func TestProposalAndVote(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := loadTestProtocol(t)
+	pr.GovActionDeposit = 100000000
+	rewardAddr := address.NewRewardAddress(network.TestNet(), address.NewKeyStakeCredential(make([]byte, 28)))
+	drepHash := stakeKey().Public().PublicKey().Hash()
+	txHash, _ := hex.DecodeString("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380")
+	anchor := tx.NewAnchor("https://example.com/rationale.json", make([]byte, 32))
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{prv})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 200000000))
+	builder.AddProposal(rewardAddr, tx.NewInfoAction(), anchor)
+	builder.AddVote(tx.NewVoter(tx.VoterDRepKey, drepHash[:]), tx.NewGovActionID(txHash, 0), tx.VoteYes, &anchor)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	body := builder.Tx().Body
+	assert.Equal(t, uint(100000000), body.ProposalProcedures[0].Deposit)
+	assert.Equal(t, uint(200000000-100000000)-uint(body.Fee), body.Outputs[0].Amount)
+
+	_, err = builder.Build()
+	assert.ErrorIs(t, err, tx.ErrMissingWitness)
+
+	builder.Sign(stakeKey())
+	txFinal, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := txFinal.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, body.VotingProcedures, decoded.Body.VotingProcedures)
+	if assert.Len(t, decoded.Body.ProposalProcedures, 1) {
+		assert.Equal(t, tx.GovActionInfo, decoded.Body.ProposalProcedures[0].GovAction.Type)
+		assert.Equal(t, anchor, decoded.Body.ProposalProcedures[0].Anchor)
+	}
+}
+
+func TestTreasuryDonation(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{prv})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+	builder.SetDonation(1000000)
+	builder.SetCurrentTreasuryValue(1500000000000000)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	body := builder.Tx().Body
+	assert.Equal(t, uint(10000000-1000000)-uint(body.Fee), body.Outputs[0].Amount)
+
+	txFinal, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := txFinal.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(1000000), decoded.Body.Donation)
+	assert.Equal(t, uint64(1500000000000000), decoded.Body.CurrentTreasuryValue)
+
+	// {0: [[hash, 0]], 1: [], 2: 200000, 21: 1000, 22: 5000000}
+	decoded, err = tx.NewTxFromHex("84a5" +
+		"0081825820fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c38000" +
+		"0180" + "021a00030d40" + "151903e8" + "161a004c4b40" + "a0f5f6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(1000), decoded.Body.CurrentTreasuryValue)
+	assert.Equal(t, uint64(5000000), decoded.Body.Donation)
+}
@@ -0,0 +1,171 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
+	"golang.org/x/crypto/blake2b"
+)
+
+type PlutusVersion uint8
+
+const (
+	PlutusV1 PlutusVersion = iota + 1
+	PlutusV2
+	PlutusV3
+)
+
+// String returns the language name of the version as used in protocol parameter cost models.
+func (v PlutusVersion) String() string {
+	return fmt.Sprintf("PlutusV%d", uint8(v))
+}
+
+// PlutusScript is a compiled Plutus script.
+type PlutusScript struct {
+	Version PlutusVersion
+	// Script is the serialized script as found in the transaction witness set,
+	// i.e. the content of the cardano-cli cborHex byte string.
+	Script []byte
+}
+
+// NewPlutusScript returns a PlutusScript from the serialized script.
+func NewPlutusScript(version PlutusVersion, script []byte) PlutusScript {
+	return PlutusScript{
+		Version: version,
+		Script:  script,
+	}
+}
+
+// NewPlutusScriptFromCBORHex returns a PlutusScript from the cborHex field of a cardano-cli script file.
+func NewPlutusScriptFromCBORHex(version PlutusVersion, cborHex string) (PlutusScript, error) {
+	script, err := GetBytesFromCBORHex(cborHex)
+	if err != nil {
+		return PlutusScript{}, err
+	}
+	return NewPlutusScript(version, script), nil
+}
+
+// Hash returns the script hash using blake2b224, prefixed by the language of the script.
+func (s PlutusScript) Hash() (Hash28, error) {
+	return Blake224Hash(append([]byte{byte(s.Version)}, s.Script...))
+}
+
+// PlutusData is a Plutus data value (datum or redeemer).
+// The value is kept in its CBOR encoding, since the hash of a datum depends on its exact bytes.
+type PlutusData struct {
+	data []byte
+}
+
+// NewPlutusDataFromCBOR returns PlutusData from its CBOR encoding.
+func NewPlutusDataFromCBOR(data []byte) (PlutusData, error) {
+	if err := cborDec.Valid(data); err != nil {
+		return PlutusData{}, err
+	}
+	return PlutusData{data: append([]byte{}, data...)}, nil
+}
+
+// NewPlutusDataFromHex returns PlutusData from its hex encoded CBOR.
+func NewPlutusDataFromHex(dataHex string) (PlutusData, error) {
+	data, err := hex.DecodeString(dataHex)
+	if err != nil {
+		return PlutusData{}, err
+	}
+	return NewPlutusDataFromCBOR(data)
+}
+
+// NewIntegerPlutusData returns PlutusData holding an integer.
+func NewIntegerPlutusData(i int64) PlutusData {
+	return mustPlutusData(i)
+}
+
+// NewBytesPlutusData returns PlutusData holding a byte string.
+func NewBytesPlutusData(b []byte) PlutusData {
+	return mustPlutusData(b)
+}
+
+// NewListPlutusData returns PlutusData holding a list of items.
+func NewListPlutusData(items ...PlutusData) PlutusData {
+	if items == nil {
+		items = []PlutusData{}
+	}
+	return mustPlutusData(items)
+}
+
+// NewMapPlutusData returns PlutusData holding a map of keys[i] to values[i].
+func NewMapPlutusData(keys, values []PlutusData) (PlutusData, error) {
+	if len(keys) != len(values) {
+		return PlutusData{}, fmt.Errorf("plutus data map has %d keys and %d values", len(keys), len(values))
+	}
+
+	data := cborHead(cborMajorTypeMap, uint64(len(keys)))
+	for i := range keys {
+		data = append(data, keys[i].data...)
+		data = append(data, values[i].data...)
+	}
+	return PlutusData{data: data}, nil
+}
+
+// NewConstrPlutusData returns PlutusData holding the constructor alternative applied to fields.
+func NewConstrPlutusData(alternative uint64, fields ...PlutusData) PlutusData {
+	if fields == nil {
+		fields = []PlutusData{}
+	}
+
+	switch {
+	case alternative < 7:
+		return mustPlutusData(cbor.Tag{Number: 121 + alternative, Content: fields})
+	case alternative < 128:
+		return mustPlutusData(cbor.Tag{Number: 1280 + alternative - 7, Content: fields})
+	}
+	return mustPlutusData(cbor.Tag{Number: 102, Content: []interface{}{alternative, fields}})
+}
+
+func mustPlutusData(v interface{}) PlutusData {
+	data, err := cborEnc.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return PlutusData{data: data}
+}
+
+// Bytes returns the CBOR encoding of the data.
+func (d PlutusData) Bytes() []byte {
+	return d.data
+}
+
+// Hash returns the datum hash of the data using blake2b256.
+func (d PlutusData) Hash() [32]byte {
+	return blake2b.Sum256(d.data)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (d PlutusData) MarshalCBOR() ([]byte, error) {
+	if d.data == nil {
+		return nil, fmt.Errorf("cbor: cannot marshal empty PlutusData")
+	}
+	return d.data, nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (d *PlutusData) UnmarshalCBOR(data []byte) error {
+	d.data = append([]byte{}, data...)
+	return nil
+}
+
+// cborHead returns the initial bytes of a CBOR data item of the major type with argument n.
+func cborHead(majorType byte, n uint64) []byte {
+	mt := majorType << 5
+	switch {
+	case n < 24:
+		return []byte{mt | byte(n)}
+	case n <= 0xff:
+		return []byte{mt | 24, byte(n)}
+	case n <= 0xffff:
+		return []byte{mt | 25, byte(n >> 8), byte(n)}
+	case n <= 0xffffffff:
+		return []byte{mt | 26, byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+	return []byte{mt | 27, byte(n >> 56), byte(n >> 48), byte(n >> 40), byte(n >> 32),
+		byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+}
@@ -0,0 +1,400 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/fees"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/protocol"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestPlutusDataCBOR(t *testing.T) {
+	datum := tx.NewConstrPlutusData(0, tx.NewIntegerPlutusData(42), tx.NewBytesPlutusData([]byte{0xca, 0xfe}))
+	assert.Equal(t, "d87982182a42cafe", hex.EncodeToString(datum.Bytes()))
+
+	assert.Equal(t, "d87980", hex.EncodeToString(tx.NewConstrPlutusData(0).Bytes()))
+	assert.Equal(t, "d9050280", hex.EncodeToString(tx.NewConstrPlutusData(9).Bytes()))
+	assert.Equal(t, "d86682188a80", hex.EncodeToString(tx.NewConstrPlutusData(138).Bytes()))
+
+	_, err := tx.NewMapPlutusData([]tx.PlutusData{tx.NewIntegerPlutusData(1)}, nil)
+	assert.Error(t, err)
+}
+
+func TestTxOutputPostAlonzo(t *testing.T) {
+	addr := address.NewEnterpriseAddress(network.TestNet(), address.NewScriptStakeCredential(make([]byte, 28)))
+	datum := tx.NewConstrPlutusData(0)
+
+	output := tx.NewTxOutputWithInlineDatum(addr, tx.NewValue(2000000), datum)
+	data, err := output.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t,
+		"a300581d70"+hex.EncodeToString(make([]byte, 28))+"011a001e8480028201d81843d87980",
+		hex.EncodeToString(data))
+
+	var decoded tx.TxOutput
+	if err := decoded.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, addr.Bytes(), decoded.Address.Bytes())
+	assert.Equal(t, uint(2000000), decoded.Amount)
+	assert.Equal(t, datum.Bytes(), decoded.Datum.Bytes())
+	assert.Nil(t, decoded.DatumHash)
+
+	// decoded map outputs keep the map format even without datum or script
+	decoded.Datum = nil
+	data, err = decoded.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, byte(0xa2), data[0])
+
+	hash := datum.Hash()
+	output = tx.NewTxOutputWithDatumHash(addr, tx.NewValue(2000000), hash[:])
+	data, err = output.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, byte(0x83), data[0])
+
+	decoded = tx.TxOutput{}
+	if err := decoded.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, hash[:], decoded.DatumHash)
+	assert.Nil(t, decoded.Datum)
+}
+
+func TestTxOutputScriptRef(t *testing.T) {
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := tx.NewPlutusScript(tx.PlutusV2, []byte{0x4e, 0x4d, 0x01, 0x00, 0x00})
+
+	output := tx.NewTxOutput(addr, 5000000)
+	output.ScriptRef = tx.NewPlutusScriptRef(script)
+	data, err := output.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded tx.TxOutput
+	if err := decoded.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, decoded.ScriptRef) && assert.NotNil(t, decoded.ScriptRef.PlutusScript) {
+		assert.Equal(t, script, *decoded.ScriptRef.PlutusScript)
+	}
+}
+
+func TestLockAtScript(t *testing.T) {
+	keyAddr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptAddr := address.NewEnterpriseAddress(network.TestNet(), address.NewScriptStakeCredential(make([]byte, 28)))
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{})
+	err = builder.LockAtScript(keyAddr, tx.NewValue(2000000), tx.NewIntegerPlutusData(1))
+	assert.True(t, errors.Is(err, tx.ErrNotScriptAddress))
+
+	assert.NoError(t, builder.LockAtScript(scriptAddr, tx.NewValue(2000000), tx.NewIntegerPlutusData(1)))
+	outputs := builder.Tx().Body.Outputs
+	assert.Len(t, outputs, 1)
+	assert.Equal(t, tx.NewIntegerPlutusData(1).Bytes(), outputs[0].Datum.Bytes())
+}
+
+func TestScriptDataHash(t *testing.T) {
+	redeemers := tx.Redeemers{tx.NewRedeemer(tx.RedeemerTagSpend, 0, tx.NewConstrPlutusData(0), tx.ExUnits{Mem: 1000, Steps: 2000})}
+	datums := []tx.PlutusData{tx.NewConstrPlutusData(0)}
+	costModels := protocol.CostModels{"PlutusV1": {1, 2}, "PlutusV2": {1, 2}}
+
+	tests := []struct {
+		name      string
+		redeemers tx.Redeemers
+		datums    []tx.PlutusData
+		languages []tx.PlutusVersion
+		preimage  string
+	}{
+		{"v2", redeemers, nil, []tx.PlutusVersion{tx.PlutusV2}, "818400" + "00d87980821903e81907d0" + "a101820102"},
+		{"v1 and v2 with datum", redeemers, datums, []tx.PlutusVersion{tx.PlutusV1, tx.PlutusV2},
+			"818400" + "00d87980821903e81907d0" + "81d87980" + "a201820102" + "4100" + "44" + "9f0102ff"},
+		{"datums only", nil, datums, nil, "a0" + "81d87980" + "a0"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			preimage, _ := hex.DecodeString(test.preimage)
+			expected := blake2b.Sum256(preimage)
+
+			hash, err := tx.ScriptDataHash(test.redeemers, test.datums, costModels, test.languages...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected[:], hash)
+		})
+	}
+
+	_, err := tx.ScriptDataHash(redeemers, nil, costModels, tx.PlutusV3)
+	assert.Error(t, err)
+
+	hash, err := tx.ScriptDataHash(nil, nil, costModels)
+	assert.NoError(t, err)
+	assert.Nil(t, hash)
+}
+
+func TestSpendFromPlutusScript(t *testing.T) {
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := loadTestProtocol(t)
+	pr.CostModels = protocol.CostModels{"PlutusV2": {1, 2, 3}}
+
+	script := tx.NewPlutusScript(tx.PlutusV2, []byte{0x4e, 0x4d, 0x01, 0x00, 0x00})
+	scriptInput := tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, 5000000)
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 2, 5000000))
+	builder.SpendFromPlutusScript(scriptInput, script, nil, tx.NewIntegerPlutusData(42), tx.ExUnits{Mem: 100, Steps: 200})
+	builder.AddOutputs(tx.NewTxOutput(addr, 2000000))
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	built, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, built.WitnessSet.Redeemers, 1) {
+		// the script input is the first of the sorted inputs
+		assert.Equal(t, uint32(0), built.WitnessSet.Redeemers[0].Index)
+	}
+	assert.Equal(t, [][]byte{script.Script}, built.WitnessSet.PlutusV2Scripts)
+	assert.Len(t, built.Body.ScriptDataHash, 32)
+
+	data, err := built.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, built.Body.ScriptDataHash, decoded.Body.ScriptDataHash)
+	assert.Equal(t, built.WitnessSet.Redeemers, decoded.WitnessSet.Redeemers)
+	assert.Equal(t, script, decoded.WitnessSet.PlutusScripts()[0])
+}
+
+func TestScriptFee(t *testing.T) {
+	scriptFee := fees.NewScriptFee(fees.NewLinearFee(44, 155381), 0.0577, 0.0000721, 15)
+
+	// 0.0577 * 1234567 + 0.0000721 * 987654321 = 142444.3924441, rounded up
+	assert.Equal(t, uint(142445), scriptFee.ExecutionFee(1234567, 987654321))
+	assert.Equal(t, uint(93750), scriptFee.ExecutionFee(1000000, 500000000))
+
+	// 25600 bytes at 15, then 25600 bytes at 18 and the rest at 21.6
+	assert.Equal(t, uint(15*1000), scriptFee.ReferenceScriptFee(1000))
+	assert.Equal(t, uint(25600*15+4400*18), scriptFee.ReferenceScriptFee(30000))
+	assert.Equal(t, uint(25600*15+25600*18+190080), scriptFee.ReferenceScriptFee(60000))
+
+	assert.Equal(t, uint(155381+44*300+142445+15*1000), scriptFee.MinFee(300, 1234567, 987654321, 1000))
+
+	// A transaction without witness set has no execution units
+	unsigned := tx.NewTx()
+	unsigned.WitnessSet = nil
+	unsignedFee, err := unsigned.Fee(scriptFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := unsigned.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint(155381+44*len(data)), unsignedFee)
+
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	newBuilder := func(pr protocol.Protocol) *tx.TxBuilder {
+		script := tx.NewPlutusScript(tx.PlutusV2, []byte{0x4e, 0x4d, 0x01, 0x00, 0x00})
+		scriptInput := tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, 5000000)
+
+		builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+		builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 2, 5000000))
+		builder.SpendFromPlutusScript(scriptInput, script, nil, tx.NewIntegerPlutusData(42), tx.ExUnits{Mem: 1234567, Steps: 987654321})
+		builder.AddOutputs(tx.NewTxOutput(addr, 2000000))
+		return builder
+	}
+
+	pr := loadTestProtocol(t)
+	pr.CostModels = protocol.CostModels{"PlutusV2": {1, 2, 3}}
+	sizeFee := minFee(t, newBuilder(pr))
+
+	pr.ExecutionUnitPrices = protocol.ExecutionUnitPrices{PriceMemory: 0.0577, PriceSteps: 0.0000721}
+	builder := newBuilder(pr)
+	assert.Equal(t, sizeFee+142445, minFee(t, builder))
+	// the script data is only set on the transaction when it is balanced or built
+	assert.Nil(t, builder.Tx().Body.ScriptDataHash)
+	assert.Empty(t, builder.Tx().WitnessSet.Redeemers)
+
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	built, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee, err := built.Fee(fees.NewScriptFee(fees.NewLinearFee(pr.TxFeePerByte, pr.TxFeeFixed), 0.0577, 0.0000721, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.GreaterOrEqual(t, built.Body.Fee, uint64(fee))
+}
+
+func TestRedeemersMapFormat(t *testing.T) {
+	// {[0, 1]: [42, [100, 200]], [1, 0]: [d87980, [1, 2]]}
+	data, _ := hex.DecodeString("a282000182182a821864" + "18c8" + "82010082d87980820102")
+
+	var redeemers tx.Redeemers
+	if err := redeemers.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tx.Redeemers{
+		tx.NewRedeemer(tx.RedeemerTagSpend, 1, tx.NewIntegerPlutusData(42), tx.ExUnits{Mem: 100, Steps: 200}),
+		tx.NewRedeemer(tx.RedeemerTagMint, 0, tx.NewConstrPlutusData(0), tx.ExUnits{Mem: 1, Steps: 2}),
+	}, redeemers)
+	assert.Equal(t, tx.ExUnits{Mem: 101, Steps: 202}, redeemers.ExUnits())
+}
+
+func TestSetCollateral(t *testing.T) {
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptAddr := address.NewEnterpriseAddress(network.TestNet(), address.NewScriptStakeCredential(make([]byte, 28)))
+	policy := testPolicy(t, "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+
+	pr := loadTestProtocol(t)
+	pr.CostModels = protocol.CostModels{"PlutusV2": {1, 2, 3}}
+	pr.CollateralPercentage = 150
+	pr.MaxCollateralInputs = 3
+	// the minimum lovelace of outputs is computed from utxoCostPerByte, not from the deprecated minUTxOValue
+	pr.UTxOCostPerByte = 4310
+	pr.MinUTXOValue = 100000
+
+	utxos := []*tx.TxInput{
+		tx.NewTxInputFromOutput("aa00000000000000000000000000000000000000000000000000000000000000", 0,
+			tx.NewTxOutputWithValue(addr, tx.NewValueWithAssets(50000000, tx.MultiAsset{policy: tx.Assets{"token": 1}}))),
+		tx.NewTxInputFromOutput("bb00000000000000000000000000000000000000000000000000000000000000", 0,
+			tx.NewTxOutput(scriptAddr, 40000000)),
+		tx.NewTxInputFromOutput("cc00000000000000000000000000000000000000000000000000000000000000", 0,
+			tx.NewTxOutput(addr, 3000000)),
+		tx.NewTxInputFromOutput("dd00000000000000000000000000000000000000000000000000000000000000", 0,
+			tx.NewTxOutput(addr, 10000000)),
+	}
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.SpendFromPlutusScript(
+		tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, tx.NewTxOutput(scriptAddr, 5000000)),
+		tx.NewPlutusScript(tx.PlutusV2, []byte{0x4e, 0x4d, 0x01, 0x00, 0x00}),
+		nil, tx.NewIntegerPlutusData(42), tx.ExUnits{Mem: 100, Steps: 200},
+	)
+	builder.SetCollateral(utxos, addr)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	body := builder.Tx().Body
+	required := (body.Fee*150 + 99) / 100
+	if assert.Len(t, body.Collateral, 1) {
+		assert.Equal(t, utxos[3].TxHash, body.Collateral[0].TxHash)
+	}
+	assert.Equal(t, required, body.TotalCollateral)
+	if assert.NotNil(t, body.CollateralReturn) {
+		assert.Equal(t, uint(10000000)-uint(required), body.CollateralReturn.Amount)
+	}
+
+	data, err := builder.Tx().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, body.TotalCollateral, decoded.Body.TotalCollateral)
+	assert.Equal(t, body.CollateralReturn.Amount, decoded.Body.CollateralReturn.Amount)
+
+	// collateral exceeding the required amount by less than the minimum lovelace of an output is forfeited
+	builder.SetCollateral([]*tx.TxInput{
+		tx.NewTxInputFromOutput("ee00000000000000000000000000000000000000000000000000000000000000", 0, tx.NewTxOutput(addr, 400000)),
+	}, addr)
+	forfeited, err := builder.BuildUnsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Less(t, uint64(required), uint64(400000))
+	assert.Nil(t, forfeited.Body.CollateralReturn)
+	assert.Equal(t, uint64(400000), forfeited.Body.TotalCollateral)
+
+	// neither the token nor the script locked output can be used as collateral
+	builder.SetCollateral(utxos[:2], addr)
+	_, err = builder.Build()
+	assert.True(t, errors.Is(err, tx.ErrNoCollateral))
+}
+
+func TestReferenceInputs(t *testing.T) {
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := loadTestProtocol(t)
+	pr.MinFeeRefScriptCostPerByte = 15
+
+	refOutput := tx.NewTxOutput(addr, 20000000)
+	refOutput.ScriptRef = tx.NewPlutusScriptRef(tx.NewPlutusScript(tx.PlutusV2, make([]byte, 1000)))
+	refInput := tx.NewTxInputFromOutput("aa00000000000000000000000000000000000000000000000000000000000000", 0, refOutput)
+
+	newBuilder := func(refInputs ...*tx.TxInput) *tx.TxBuilder {
+		builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+		builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+		builder.AddReferenceInputs(refInputs...)
+		builder.AddOutputs(tx.NewTxOutput(addr, 2000000))
+		return builder
+	}
+
+	builder := newBuilder(refInput)
+	totalI, _ := builder.GetTotalInputOutputs()
+	assert.Equal(t, uint(10000000), totalI)
+
+	// the reference inputs field costs its encoded size, plus 15 lovelace per reference script byte
+	refInputsSize := pr.TxFeePerByte * (1 + 1 + 36)
+	assert.Equal(t, minFee(t, newBuilder())+refInputsSize+15*1000, minFee(t, builder))
+
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	outputs := builder.Tx().Body.Outputs
+	assert.Equal(t, uint(10000000-2000000)-uint(builder.Tx().Body.Fee), outputs[len(outputs)-1].Amount)
+
+	data, err := builder.Tx().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, decoded.Body.ReferenceInputs, 1) {
+		assert.Equal(t, refInput.TxHash, decoded.Body.ReferenceInputs[0].TxHash)
+	}
+}
@@ -3,6 +3,7 @@ package tx
 import (
 	"fmt"
 
+	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
 	"golang.org/x/crypto/blake2b"
 )
 
@@ -71,6 +72,8 @@ type NativeScript struct {
 	N             uint64
 	Scripts       []NativeScript
 	IntervalValue uint64
+
+	raw rawBytes
 }
 
 // NewScriptPubKey returns a new Script PubKey.
@@ -91,6 +94,18 @@ func Blake224Hash(b []byte) ([]byte, error) {
 	return hash.Sum(nil), err
 }
 
+// keyHashes returns the key hashes of all the ScriptPubKey scripts of the script.
+func (ns *NativeScript) keyHashes() []AddrKeyHash {
+	if ns.Type == ScriptPubKey {
+		return []AddrKeyHash{ns.KeyHash}
+	}
+	keyHashes := []AddrKeyHash{}
+	for i := range ns.Scripts {
+		keyHashes = append(keyHashes, ns.Scripts[i].keyHashes()...)
+	}
+	return keyHashes
+}
+
 // Hash returns the script hash using blake2b224.
 func (ns *NativeScript) Hash() (Hash28, error) {
 	bytes, err := ns.Bytes()
@@ -98,7 +113,7 @@ func (ns *NativeScript) Hash() (Hash28, error) {
 		return nil, err
 	}
 	bytes = append([]byte{byte(NativeScriptNamespace)}, bytes...)
-	return Blake224Hash(append(bytes))
+	return Blake224Hash(bytes)
 }
 
 // Bytes returns the CBOR encoding of the script as bytes.
@@ -107,7 +122,16 @@ func (ns *NativeScript) Bytes() ([]byte, error) {
 }
 
 // MarshalCBOR implements cbor.Marshaler.
+// A decoded script that has not been modified is returned with its original bytes.
 func (ns *NativeScript) MarshalCBOR() ([]byte, error) {
+	enc, err := ns.marshalCBOR()
+	if err != nil {
+		return nil, err
+	}
+	return ns.raw.bytes(enc), nil
+}
+
+func (ns *NativeScript) marshalCBOR() ([]byte, error) {
 	var script []interface{}
 	switch ns.Type {
 	case ScriptPubKey:
@@ -124,6 +148,20 @@ func (ns *NativeScript) MarshalCBOR() ([]byte, error) {
 
 // UnmarshalCBOR implements cbor.Unmarshaler.
 func (ns *NativeScript) UnmarshalCBOR(data []byte) error {
+	if err := ns.unmarshalCBOR(data); err != nil {
+		return err
+	}
+
+	enc, err := ns.marshalCBOR()
+	if err != nil {
+		return err
+	}
+	ns.raw.keep(data, enc)
+
+	return nil
+}
+
+func (ns *NativeScript) unmarshalCBOR(data []byte) error {
 	nsType, err := getTypeFromCBORArray(data)
 	if err != nil {
 		return fmt.Errorf("cbor: cannot unmarshal CBOR array into StakeCredential (%v)", err)
@@ -177,3 +215,94 @@ func (ns *NativeScript) UnmarshalCBOR(data []byte) error {
 
 	return nil
 }
+
+// ScriptRef is a script stored in a transaction output, to be used by transactions referencing the output.
+// Exactly one of NativeScript and PlutusScript is set.
+type ScriptRef struct {
+	NativeScript *NativeScript
+	PlutusScript *PlutusScript
+}
+
+// NewNativeScriptRef returns a pointer to a ScriptRef holding a native script.
+func NewNativeScriptRef(script NativeScript) *ScriptRef {
+	return &ScriptRef{NativeScript: &script}
+}
+
+// NewPlutusScriptRef returns a pointer to a ScriptRef holding a Plutus script.
+func NewPlutusScriptRef(script PlutusScript) *ScriptRef {
+	return &ScriptRef{PlutusScript: &script}
+}
+
+// script returns the CBOR encoding of the script as [type, script].
+func (s *ScriptRef) script() ([]byte, error) {
+	switch {
+	case s.NativeScript != nil:
+		return cborEnc.Marshal([]interface{}{uint64(0), s.NativeScript})
+	case s.PlutusScript != nil:
+		return cborEnc.Marshal([]interface{}{uint64(s.PlutusScript.Version), s.PlutusScript.Script})
+	}
+	return nil, fmt.Errorf("cbor: empty ScriptRef")
+}
+
+// Size returns the size of the script in bytes, as charged by the reference script fee.
+func (s *ScriptRef) Size() (int, error) {
+	if s.PlutusScript != nil {
+		return len(s.PlutusScript.Script), nil
+	}
+	if s.NativeScript == nil {
+		return 0, fmt.Errorf("empty ScriptRef")
+	}
+	data, err := s.NativeScript.MarshalCBOR()
+	if err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+// hash returns the hash of the script.
+func (s *ScriptRef) hash() (Hash28, error) {
+	switch {
+	case s.NativeScript != nil:
+		return s.NativeScript.Hash()
+	case s.PlutusScript != nil:
+		return s.PlutusScript.Hash()
+	}
+	return nil, fmt.Errorf("empty ScriptRef")
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (s *ScriptRef) MarshalCBOR() ([]byte, error) {
+	script, err := s.script()
+	if err != nil {
+		return nil, err
+	}
+	return wrapCBOR(script)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (s *ScriptRef) UnmarshalCBOR(data []byte) error {
+	content, err := unwrapCBOR(data)
+	if err != nil {
+		return err
+	}
+
+	var script struct {
+		_      struct{} `cbor:",toarray"`
+		Type   uint64
+		Script cbor.RawMessage
+	}
+	if err := cborDec.Unmarshal(content, &script); err != nil {
+		return err
+	}
+
+	*s = ScriptRef{}
+	switch script.Type {
+	case 0:
+		s.NativeScript = &NativeScript{}
+		return cborDec.Unmarshal(script.Script, s.NativeScript)
+	case uint64(PlutusV1), uint64(PlutusV2), uint64(PlutusV3):
+		s.PlutusScript = &PlutusScript{Version: PlutusVersion(script.Type)}
+		return cborDec.Unmarshal(script.Script, &s.PlutusScript.Script)
+	}
+	return fmt.Errorf("cbor: unknown script type %d", script.Type)
+}
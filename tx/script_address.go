@@ -0,0 +1,47 @@
+package tx
+
+import (
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+)
+
+// Script is a native or Plutus script, e.g. a *NativeScript or a PlutusScript.
+type Script interface {
+	Hash() (Hash28, error)
+}
+
+// NewScriptCredential returns the credential of the script hash.
+func NewScriptCredential(script Script) (*address.StakeCredential, error) {
+	hash, err := script.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return address.NewScriptStakeCredential(hash), nil
+}
+
+// NewScriptEnterpriseAddress returns the address of outputs locked by the script, without a stake credential.
+func NewScriptEnterpriseAddress(net *network.NetworkInfo, script Script) (*address.EnterpriseAddress, error) {
+	payment, err := NewScriptCredential(script)
+	if err != nil {
+		return nil, err
+	}
+	return address.NewEnterpriseAddress(net, payment), nil
+}
+
+// NewScriptBaseAddress returns the address of outputs locked by the script, delegated with the stake credential.
+func NewScriptBaseAddress(net *network.NetworkInfo, script Script, stake *address.StakeCredential) (*address.BaseAddress, error) {
+	payment, err := NewScriptCredential(script)
+	if err != nil {
+		return nil, err
+	}
+	return address.NewBaseAddress(net, payment, stake), nil
+}
+
+// NewScriptRewardAddress returns the reward address of the script stake credential.
+func NewScriptRewardAddress(net *network.NetworkInfo, script Script) (*address.RewardAddress, error) {
+	stake, err := NewScriptCredential(script)
+	if err != nil {
+		return nil, err
+	}
+	return address.NewRewardAddress(net, stake), nil
+}
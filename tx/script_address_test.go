@@ -0,0 +1,74 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptAddresses(t *testing.T) {
+	// the always succeeds script of the cardano-node Plutus examples, locking funds at
+	// addr_test1wpnlxv2xv9a9ucvnvzqakwepzl9ltx7jzgm53av2e9ncv4sysemm8
+	alwaysSucceeds, err := hex.DecodeString("4d01000033222220051200120011")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plutusScript := tx.NewPlutusScript(tx.PlutusV1, alwaysSucceeds)
+	plutusHash, err := plutusScript.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "67f33146617a5e61936081db3b2117cbf59bd2123748f58ac9678656", hex.EncodeToString(plutusHash))
+	plutusAddr, err := tx.NewScriptEnterpriseAddress(network.TestNet(), plutusScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "addr_test1wpnlxv2xv9a9ucvnvzqakwepzl9ltx7jzgm53av2e9ncv4sysemm8", plutusAddr.String())
+
+	nativeScript := &tx.NativeScript{Type: tx.ScriptAll, Scripts: []tx.NativeScript{pubKeyScript(1), pubKeyScript(2)}}
+	nativeHash, err := nativeScript.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stake := address.NewKeyStakeCredential(keyHash(3))
+	for _, sc := range []struct {
+		description string
+		script      tx.Script
+		hash        tx.Hash28
+	}{
+		{"native script", nativeScript, nativeHash},
+		{"plutus script", plutusScript, plutusHash},
+	} {
+		t.Run(sc.description, func(t *testing.T) {
+			enterprise, err := tx.NewScriptEnterpriseAddress(network.TestNet(), sc.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, address.ScriptStakeCredentialType, enterprise.Payment.Kind)
+			assert.Equal(t, []byte(sc.hash), enterprise.Payment.Payload)
+			assert.True(t, strings.HasPrefix(enterprise.String(), "addr_test1w"), enterprise.String())
+
+			base, err := tx.NewScriptBaseAddress(network.MainNet(), sc.script, stake)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, []byte(sc.hash), base.Payment.Payload)
+			assert.Equal(t, stake.Payload, base.Stake.Payload)
+			assert.True(t, strings.HasPrefix(base.String(), "addr1z"), base.String())
+
+			reward, err := tx.NewScriptRewardAddress(network.TestNet(), sc.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, address.ScriptStakeCredentialType, reward.Stake.Kind)
+			assert.Equal(t, []byte(sc.hash), reward.Stake.Payload)
+			assert.True(t, strings.HasPrefix(reward.String(), "stake_test17"), reward.String())
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package tx
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/milos-ethernal/go-cardano-serialization/protocol"
+	"golang.org/x/crypto/blake2b"
+)
+
+type RedeemerTag uint64
+
+const (
+	RedeemerTagSpend RedeemerTag = iota
+	RedeemerTagMint
+	RedeemerTagCert
+	RedeemerTagReward
+	RedeemerTagVoting
+	RedeemerTagProposing
+)
+
+// ExUnits are the execution units (memory and CPU steps) a script is allowed to use.
+type ExUnits struct {
+	_     struct{} `cbor:",toarray"`
+	Mem   uint64
+	Steps uint64
+}
+
+// Redeemer is the argument passed to the script validating the Index-th item of the kind given by Tag
+// (e.g. the Index-th input of the sorted inputs for RedeemerTagSpend).
+type Redeemer struct {
+	_       struct{} `cbor:",toarray"`
+	Tag     RedeemerTag
+	Index   uint32
+	Data    PlutusData
+	ExUnits ExUnits
+}
+
+// NewRedeemer returns a Redeemer.
+func NewRedeemer(tag RedeemerTag, index uint32, data PlutusData, exUnits ExUnits) Redeemer {
+	return Redeemer{
+		Tag:     tag,
+		Index:   index,
+		Data:    data,
+		ExUnits: exUnits,
+	}
+}
+
+// Redeemers are the redeemers of a witness set.
+// They are encoded as an array; the Conway map format is accepted when decoding.
+type Redeemers []Redeemer
+
+type redeemerKey struct {
+	_     struct{} `cbor:",toarray"`
+	Tag   RedeemerTag
+	Index uint32
+}
+
+type redeemerValue struct {
+	_       struct{} `cbor:",toarray"`
+	Data    PlutusData
+	ExUnits ExUnits
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (r *Redeemers) UnmarshalCBOR(data []byte) error {
+	if len(data) == 0 || data[0]>>5 != cborMajorTypeMap {
+		var redeemers []Redeemer
+		if err := cborDec.Unmarshal(data, &redeemers); err != nil {
+			return err
+		}
+		*r = redeemers
+		return nil
+	}
+
+	m := map[redeemerKey]redeemerValue{}
+	if err := cborDec.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	redeemers := make(Redeemers, 0, len(m))
+	for key, value := range m {
+		redeemers = append(redeemers, NewRedeemer(key.Tag, key.Index, value.Data, value.ExUnits))
+	}
+	sort.Slice(redeemers, func(i, j int) bool {
+		if redeemers[i].Tag != redeemers[j].Tag {
+			return redeemers[i].Tag < redeemers[j].Tag
+		}
+		return redeemers[i].Index < redeemers[j].Index
+	})
+	*r = redeemers
+
+	return nil
+}
+
+// ExUnits returns the total execution units of the redeemers.
+func (r Redeemers) ExUnits() ExUnits {
+	total := ExUnits{}
+	for _, redeemer := range r {
+		total.Mem += redeemer.ExUnits.Mem
+		total.Steps += redeemer.ExUnits.Steps
+	}
+	return total
+}
+
+// ScriptDataHash returns the script data hash (TxBody key 11) of the redeemers and datums, together with
+// the language views of the cost models of the Plutus languages used by the transaction.
+// It returns nil if there are neither redeemers nor datums.
+func ScriptDataHash(redeemers Redeemers, datums []PlutusData, costModels protocol.CostModels, languages ...PlutusVersion) ([]byte, error) {
+	if len(redeemers) == 0 && len(datums) == 0 {
+		return nil, nil
+	}
+
+	// With datums but no redeemers, both the redeemers and the language views are empty maps.
+	data := []byte{0xa0}
+	views := []byte{0xa0}
+	if len(redeemers) > 0 {
+		enc, err := cborEnc.Marshal([]Redeemer(redeemers))
+		if err != nil {
+			return nil, err
+		}
+		data = enc
+
+		views, err = languageViews(costModels, languages)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(datums) > 0 {
+		enc, err := cborEnc.Marshal(datums)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, enc...)
+	}
+	data = append(data, views...)
+
+	hash := blake2b.Sum256(data)
+	return hash[:], nil
+}
+
+// languageViews returns the encoding of the cost models of the languages as hashed in the script data hash.
+// For historical reasons PlutusV1 uses a byte string key and an indefinite length list wrapped in a byte string.
+func languageViews(costModels protocol.CostModels, languages []PlutusVersion) ([]byte, error) {
+	type view struct {
+		key, value []byte
+	}
+
+	seen := map[PlutusVersion]bool{}
+	views := []view{}
+	for _, lang := range languages {
+		if seen[lang] {
+			continue
+		}
+		seen[lang] = true
+
+		costs, ok := costModels[lang.String()]
+		if !ok {
+			return nil, fmt.Errorf("missing cost model for %s", lang)
+		}
+
+		var v view
+		var err error
+		if lang == PlutusV1 {
+			list := []byte{0x9f}
+			for _, cost := range costs {
+				enc, err := cborEnc.Marshal(cost)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, enc...)
+			}
+			list = append(list, 0xff)
+
+			if v.key, err = cborEnc.Marshal([]byte{0}); err != nil {
+				return nil, err
+			}
+			if v.value, err = cborEnc.Marshal(list); err != nil {
+				return nil, err
+			}
+		} else {
+			if v.key, err = cborEnc.Marshal(uint64(lang) - 1); err != nil {
+				return nil, err
+			}
+			if v.value, err = cborEnc.Marshal(costs); err != nil {
+				return nil, err
+			}
+		}
+		views = append(views, v)
+	}
+
+	// canonical map key order: shorter keys first, then bytewise
+	sort.Slice(views, func(i, j int) bool {
+		if len(views[i].key) != len(views[j].key) {
+			return len(views[i].key) < len(views[j].key)
+		}
+		return bytes.Compare(views[i].key, views[j].key) < 0
+	})
+
+	data := cborHead(cborMajorTypeMap, uint64(len(views)))
+	for _, v := range views {
+		data = append(data, v.key...)
+		data = append(data, v.value...)
+	}
+	return data, nil
+}
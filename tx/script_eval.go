@@ -0,0 +1,304 @@
+package tx
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+)
+
+var ErrScriptNotSatisfiable = errors.New("native script cannot be satisfied")
+
+// Evaluate reports whether the script is satisfied by a transaction signed by the signers and valid from
+// the slot validityStart until the slot ttl (exclusive). As in the transaction body, a zero validityStart
+// or ttl means that the transaction is not bounded, which fails ScriptInvalidBefore and ScriptInvalidAfter.
+func (ns *NativeScript) Evaluate(signers []AddrKeyHash, validityStart, ttl uint64) bool {
+	switch ns.Type {
+	case ScriptPubKey:
+		for _, signer := range signers {
+			if bytes.Equal(signer, ns.KeyHash) {
+				return true
+			}
+		}
+		return false
+	case ScriptAll:
+		for i := range ns.Scripts {
+			if !ns.Scripts[i].Evaluate(signers, validityStart, ttl) {
+				return false
+			}
+		}
+		return true
+	case ScriptAny:
+		for i := range ns.Scripts {
+			if ns.Scripts[i].Evaluate(signers, validityStart, ttl) {
+				return true
+			}
+		}
+		return false
+	case ScriptNofK:
+		satisfied := uint64(0)
+		for i := range ns.Scripts {
+			if ns.Scripts[i].Evaluate(signers, validityStart, ttl) {
+				satisfied++
+			}
+		}
+		return satisfied >= ns.N
+	case ScriptInvalidBefore:
+		return validityStart != 0 && validityStart >= ns.IntervalValue
+	case ScriptInvalidAfter:
+		return ttl != 0 && ttl <= ns.IntervalValue
+	}
+	return false
+}
+
+// ScriptSatisfaction is a set of signers and a validity interval that satisfy a native script.
+type ScriptSatisfaction struct {
+	// KeyHashes are the keys that have to sign the transaction, sorted.
+	KeyHashes []AddrKeyHash
+	// ValidityStart is the lowest validity interval start of the transaction, or zero if it is not constrained.
+	ValidityStart uint64
+	// TTL is the highest TTL of the transaction, or zero if it is not constrained.
+	TTL uint64
+}
+
+// merge returns the satisfaction of both requirements, or false if their validity intervals do not overlap.
+func (s ScriptSatisfaction) merge(other ScriptSatisfaction) (ScriptSatisfaction, bool) {
+	merged := ScriptSatisfaction{
+		KeyHashes:     append(append([]AddrKeyHash{}, s.KeyHashes...), other.KeyHashes...),
+		ValidityStart: s.ValidityStart,
+		TTL:           s.TTL,
+	}
+	if other.ValidityStart > merged.ValidityStart {
+		merged.ValidityStart = other.ValidityStart
+	}
+	if other.TTL != 0 && (merged.TTL == 0 || other.TTL < merged.TTL) {
+		merged.TTL = other.TTL
+	}
+
+	sort.Slice(merged.KeyHashes, func(i, j int) bool {
+		return bytes.Compare(merged.KeyHashes[i], merged.KeyHashes[j]) < 0
+	})
+	keyHashes := merged.KeyHashes[:0]
+	for i, keyHash := range merged.KeyHashes {
+		if i == 0 || !bytes.Equal(keyHash, merged.KeyHashes[i-1]) {
+			keyHashes = append(keyHashes, keyHash)
+		}
+	}
+	merged.KeyHashes = keyHashes
+
+	return merged, merged.TTL == 0 || merged.ValidityStart < merged.TTL
+}
+
+// MinimalSatisfaction returns the fewest signers, and the widest validity interval, satisfying the script,
+// so that only these keys have to be asked for a signature.
+// ErrScriptNotSatisfiable is returned if no transaction can satisfy the script.
+func (ns *NativeScript) MinimalSatisfaction() (ScriptSatisfaction, error) {
+	satisfaction, ok := ns.minimalSatisfaction(ScriptSatisfaction{})
+	if !ok {
+		return ScriptSatisfaction{}, ErrScriptNotSatisfiable
+	}
+	return satisfaction, nil
+}
+
+// minimalSatisfaction returns the fewest signers, and the widest validity interval, satisfying the script
+// together with the signers and within the validity interval of bound, or false if there are none.
+func (ns *NativeScript) minimalSatisfaction(bound ScriptSatisfaction) (ScriptSatisfaction, bool) {
+	satisfactions := ns.satisfactions(bound)
+	if len(satisfactions) == 0 {
+		return ScriptSatisfaction{}, false
+	}
+
+	best := satisfactions[0]
+	for _, satisfaction := range satisfactions[1:] {
+		if satisfaction.better(best) {
+			best = satisfaction
+		}
+	}
+	return best, true
+}
+
+// better reports whether the satisfaction has fewer signers than other, or as many signers and a wider
+// validity interval.
+func (s ScriptSatisfaction) better(other ScriptSatisfaction) bool {
+	if len(s.KeyHashes) != len(other.KeyHashes) {
+		return len(s.KeyHashes) < len(other.KeyHashes)
+	}
+	if s.ValidityStart != other.ValidityStart {
+		return s.ValidityStart < other.ValidityStart
+	}
+	return other.TTL != 0 && (s.TTL == 0 || s.TTL > other.TTL)
+}
+
+// covers reports whether the satisfaction requires a subset of the signers of other and a validity interval
+// containing the interval of other, so that other never has to be considered.
+func (s ScriptSatisfaction) covers(other ScriptSatisfaction) bool {
+	if s.ValidityStart > other.ValidityStart || (s.TTL != 0 && (other.TTL == 0 || s.TTL < other.TTL)) {
+		return false
+	}
+	signers := map[string]bool{}
+	for _, keyHash := range other.KeyHashes {
+		signers[string(keyHash)] = true
+	}
+	for _, keyHash := range s.KeyHashes {
+		if !signers[string(keyHash)] {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfactions returns the ways of satisfying the script together with the requirements of bound,
+// leaving out the ones covered by another. The result is empty if the script cannot be satisfied within bound.
+func (ns *NativeScript) satisfactions(bound ScriptSatisfaction) []ScriptSatisfaction {
+	var requirement ScriptSatisfaction
+	switch ns.Type {
+	case ScriptPubKey:
+		requirement = ScriptSatisfaction{KeyHashes: []AddrKeyHash{ns.KeyHash}}
+	case ScriptAll:
+		return ns.satisfyN(uint64(len(ns.Scripts)), bound)
+	case ScriptAny:
+		return ns.satisfyN(1, bound)
+	case ScriptNofK:
+		return ns.satisfyN(ns.N, bound)
+	case ScriptInvalidBefore:
+		requirement = ScriptSatisfaction{ValidityStart: ns.IntervalValue}
+	case ScriptInvalidAfter:
+		// the transaction has to be valid for at least one slot
+		if ns.IntervalValue == 0 {
+			return nil
+		}
+		requirement = ScriptSatisfaction{TTL: ns.IntervalValue}
+	default:
+		return nil
+	}
+
+	if merged, ok := bound.merge(requirement); ok {
+		return []ScriptSatisfaction{merged}
+	}
+	return nil
+}
+
+// satisfyN returns the ways of satisfying n of the sub scripts together with the requirements of bound.
+// When time locks are involved, every alternative of every sub script is tried, so a sub script whose
+// cheapest alternative conflicts with the others may still be satisfied by another of its alternatives.
+// Otherwise the alternatives only differ by their signers and the search keeps the fewest signers for
+// every count of satisfied sub scripts, so that large multisig scripts are satisfied in polynomial time.
+func (ns *NativeScript) satisfyN(n uint64, bound ScriptSatisfaction) []ScriptSatisfaction {
+	if n > uint64(len(ns.Scripts)) {
+		return nil
+	}
+	if bound.KeyHashes == nil {
+		bound, _ = bound.merge(ScriptSatisfaction{})
+	}
+	if keyHashes, ok := ns.pubKeyHashes(); ok {
+		return []ScriptSatisfaction{satisfyKeys(n, keyHashes, bound)}
+	}
+	timeLocked := ns.timeLocked()
+
+	// satisfied[j] are the ways of satisfying j of the sub scripts seen so far
+	satisfied := make([][]ScriptSatisfaction, n+1)
+	satisfied[0] = []ScriptSatisfaction{bound}
+	for i := range ns.Scripts {
+		for j := n; j > 0; j-- {
+			for _, partial := range satisfied[j-1] {
+				satisfied[j] = addSatisfactions(satisfied[j], ns.Scripts[i].satisfactions(partial)...)
+			}
+			if !timeLocked && len(satisfied[j]) > 1 {
+				best := satisfied[j][0]
+				for _, satisfaction := range satisfied[j][1:] {
+					if satisfaction.better(best) {
+						best = satisfaction
+					}
+				}
+				satisfied[j] = []ScriptSatisfaction{best}
+			}
+		}
+	}
+
+	return satisfied[n]
+}
+
+// pubKeyHashes returns the key hashes of the sub scripts, or false if one of them is not a ScriptPubKey.
+func (ns *NativeScript) pubKeyHashes() ([]AddrKeyHash, bool) {
+	keyHashes := make([]AddrKeyHash, 0, len(ns.Scripts))
+	for _, script := range ns.Scripts {
+		if script.Type != ScriptPubKey {
+			return nil, false
+		}
+		keyHashes = append(keyHashes, script.KeyHash)
+	}
+	return keyHashes, true
+}
+
+// timeLocked reports whether the script or one of its sub scripts is a ScriptInvalidBefore or ScriptInvalidAfter.
+func (ns *NativeScript) timeLocked() bool {
+	if ns.Type == ScriptInvalidBefore || ns.Type == ScriptInvalidAfter {
+		return true
+	}
+	for i := range ns.Scripts {
+		if ns.Scripts[i].timeLocked() {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfyKeys returns the fewest signers, in addition to the signers of bound, for n of the key hashes
+// to be signed: the signers of bound first, then the keys required by the most sub scripts.
+func satisfyKeys(n uint64, keyHashes []AddrKeyHash, bound ScriptSatisfaction) ScriptSatisfaction {
+	signed := map[string]bool{}
+	for _, keyHash := range bound.KeyHashes {
+		signed[string(keyHash)] = true
+	}
+	counts := map[string]uint64{}
+	unsigned := []AddrKeyHash{}
+	satisfied := uint64(0)
+	for _, keyHash := range keyHashes {
+		if signed[string(keyHash)] {
+			satisfied++
+			continue
+		}
+		if counts[string(keyHash)] == 0 {
+			unsigned = append(unsigned, keyHash)
+		}
+		counts[string(keyHash)]++
+	}
+	sort.SliceStable(unsigned, func(i, j int) bool {
+		return counts[string(unsigned[i])] > counts[string(unsigned[j])]
+	})
+
+	signers := []AddrKeyHash{}
+	for _, keyHash := range unsigned {
+		if satisfied >= n {
+			break
+		}
+		signers = append(signers, keyHash)
+		satisfied += counts[string(keyHash)]
+	}
+	merged, _ := bound.merge(ScriptSatisfaction{KeyHashes: signers})
+	return merged
+}
+
+// addSatisfactions adds the satisfactions to the list, leaving out the ones covered by another.
+func addSatisfactions(list []ScriptSatisfaction, satisfactions ...ScriptSatisfaction) []ScriptSatisfaction {
+	for _, satisfaction := range satisfactions {
+		covered := false
+		for _, other := range list {
+			if other.covers(satisfaction) {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+
+		kept := list[:0]
+		for _, other := range list {
+			if !satisfaction.covers(other) {
+				kept = append(kept, other)
+			}
+		}
+		list = append(kept, satisfaction)
+	}
+	return list
+}
@@ -0,0 +1,316 @@
+package tx_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func keyHash(b byte) tx.AddrKeyHash {
+	return bytes.Repeat([]byte{b}, 28)
+}
+
+func pubKeyScript(b byte) tx.NativeScript {
+	return tx.NativeScript{Type: tx.ScriptPubKey, KeyHash: keyHash(b)}
+}
+
+func TestNativeScriptEvaluate(t *testing.T) {
+	// 2 of 3 keys, or key 4 after slot 100 and before slot 200
+	script := tx.NativeScript{
+		Type: tx.ScriptAny,
+		Scripts: []tx.NativeScript{
+			{
+				Type:    tx.ScriptNofK,
+				N:       2,
+				Scripts: []tx.NativeScript{pubKeyScript(1), pubKeyScript(2), pubKeyScript(3)},
+			},
+			{
+				Type: tx.ScriptAll,
+				Scripts: []tx.NativeScript{
+					pubKeyScript(4),
+					{Type: tx.ScriptInvalidBefore, IntervalValue: 100},
+					{Type: tx.ScriptInvalidAfter, IntervalValue: 200},
+				},
+			},
+		},
+	}
+
+	scenarios := []struct {
+		description   string
+		signers       []tx.AddrKeyHash
+		validityStart uint64
+		ttl           uint64
+		expected      bool
+	}{
+		{"two of three", []tx.AddrKeyHash{keyHash(1), keyHash(3)}, 0, 0, true},
+		{"one of three", []tx.AddrKeyHash{keyHash(1)}, 0, 0, false},
+		{"time locked key within the interval", []tx.AddrKeyHash{keyHash(4)}, 100, 200, true},
+		{"time locked key without validity start", []tx.AddrKeyHash{keyHash(4)}, 0, 200, false},
+		{"time locked key with a later ttl", []tx.AddrKeyHash{keyHash(4)}, 150, 201, false},
+		{"time locked key with an earlier start", []tx.AddrKeyHash{keyHash(4)}, 99, 150, false},
+		{"no signers", []tx.AddrKeyHash{}, 100, 200, false},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.description, func(t *testing.T) {
+			assert.Equal(t, sc.expected, script.Evaluate(sc.signers, sc.validityStart, sc.ttl))
+		})
+	}
+
+	assert.True(t, (&tx.NativeScript{Type: tx.ScriptAll}).Evaluate(nil, 0, 0))
+	assert.False(t, (&tx.NativeScript{Type: tx.ScriptAny}).Evaluate(nil, 0, 0))
+}
+
+func TestNativeScriptMinimalSatisfaction(t *testing.T) {
+	timeLocked := tx.NativeScript{
+		Type: tx.ScriptAll,
+		Scripts: []tx.NativeScript{
+			pubKeyScript(4),
+			{Type: tx.ScriptInvalidBefore, IntervalValue: 100},
+			{Type: tx.ScriptInvalidAfter, IntervalValue: 200},
+		},
+	}
+	twoOfThree := tx.NativeScript{
+		Type:    tx.ScriptNofK,
+		N:       2,
+		Scripts: []tx.NativeScript{pubKeyScript(3), pubKeyScript(1), pubKeyScript(2)},
+	}
+	// 10 of 20 keys, and 10 of 20 scripts requiring a key each
+	tenOfTwenty := tx.NativeScript{Type: tx.ScriptNofK, N: 10}
+	nestedTenOfTwenty := tx.NativeScript{Type: tx.ScriptNofK, N: 10}
+	tenKeys := []tx.AddrKeyHash{}
+	for i := byte(1); i <= 20; i++ {
+		tenOfTwenty.Scripts = append(tenOfTwenty.Scripts, pubKeyScript(i))
+		nestedTenOfTwenty.Scripts = append(nestedTenOfTwenty.Scripts, tx.NativeScript{Type: tx.ScriptAll, Scripts: []tx.NativeScript{pubKeyScript(i)}})
+		if i <= 10 {
+			tenKeys = append(tenKeys, keyHash(i))
+		}
+	}
+
+	scenarios := []struct {
+		description string
+		script      tx.NativeScript
+		expected    tx.ScriptSatisfaction
+		err         error
+	}{
+		{
+			description: "n of k",
+			script:      twoOfThree,
+			expected:    tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{keyHash(1), keyHash(3)}},
+		},
+		{
+			description: "time lock",
+			script:      timeLocked,
+			expected:    tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{keyHash(4)}, ValidityStart: 100, TTL: 200},
+		},
+		{
+			description: "fewest signers",
+			script:      tx.NativeScript{Type: tx.ScriptAny, Scripts: []tx.NativeScript{twoOfThree, timeLocked}},
+			expected:    tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{keyHash(4)}, ValidityStart: 100, TTL: 200},
+		},
+		{
+			description: "shared keys",
+			script:      tx.NativeScript{Type: tx.ScriptAll, Scripts: []tx.NativeScript{twoOfThree, pubKeyScript(1)}},
+			expected:    tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{keyHash(1), keyHash(3)}},
+		},
+		{
+			description: "disjoint validity intervals",
+			script: tx.NativeScript{
+				Type: tx.ScriptAll,
+				Scripts: []tx.NativeScript{
+					{Type: tx.ScriptInvalidBefore, IntervalValue: 200},
+					{Type: tx.ScriptInvalidAfter, IntervalValue: 100},
+				},
+			},
+			err: tx.ErrScriptNotSatisfiable,
+		},
+		{
+			description: "alternative time lock",
+			script: tx.NativeScript{
+				Type: tx.ScriptAll,
+				Scripts: []tx.NativeScript{
+					{
+						Type: tx.ScriptAny,
+						Scripts: []tx.NativeScript{
+							{Type: tx.ScriptInvalidAfter, IntervalValue: 100},
+							{Type: tx.ScriptInvalidBefore, IntervalValue: 300},
+						},
+					},
+					{Type: tx.ScriptInvalidBefore, IntervalValue: 200},
+				},
+			},
+			expected: tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{}, ValidityStart: 300},
+		},
+		{
+			description: "more signers outside the validity interval of the fewest",
+			script: tx.NativeScript{
+				Type: tx.ScriptAll,
+				Scripts: []tx.NativeScript{
+					{
+						Type: tx.ScriptAny,
+						Scripts: []tx.NativeScript{
+							{Type: tx.ScriptAll, Scripts: []tx.NativeScript{pubKeyScript(1), {Type: tx.ScriptInvalidAfter, IntervalValue: 100}}},
+							{Type: tx.ScriptAll, Scripts: []tx.NativeScript{pubKeyScript(1), pubKeyScript(2)}},
+						},
+					},
+					{Type: tx.ScriptInvalidBefore, IntervalValue: 200},
+				},
+			},
+			expected: tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{keyHash(1), keyHash(2)}, ValidityStart: 200},
+		},
+		{
+			description: "large n of k",
+			script:      tenOfTwenty,
+			expected:    tx.ScriptSatisfaction{KeyHashes: tenKeys},
+		},
+		{
+			description: "large n of k sub scripts with a time lock",
+			script: tx.NativeScript{
+				Type:    tx.ScriptAll,
+				Scripts: []tx.NativeScript{nestedTenOfTwenty, {Type: tx.ScriptInvalidBefore, IntervalValue: 100}},
+			},
+			expected: tx.ScriptSatisfaction{KeyHashes: tenKeys, ValidityStart: 100},
+		},
+		{
+			description: "duplicate keys",
+			script: tx.NativeScript{
+				Type:    tx.ScriptNofK,
+				N:       3,
+				Scripts: []tx.NativeScript{pubKeyScript(1), pubKeyScript(2), pubKeyScript(3), pubKeyScript(2), pubKeyScript(4)},
+			},
+			expected: tx.ScriptSatisfaction{KeyHashes: []tx.AddrKeyHash{keyHash(1), keyHash(2)}},
+		},
+		{
+			description: "not enough keys",
+			script:      tx.NativeScript{Type: tx.ScriptNofK, N: 3, Scripts: []tx.NativeScript{pubKeyScript(1), pubKeyScript(2)}},
+			err:         tx.ErrScriptNotSatisfiable,
+		},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.description, func(t *testing.T) {
+			satisfaction, err := sc.script.MinimalSatisfaction()
+			if sc.err != nil {
+				assert.ErrorIs(t, err, sc.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, sc.expected, satisfaction)
+			assert.True(t, sc.script.Evaluate(satisfaction.KeyHashes, satisfaction.ValidityStart, satisfaction.TTL))
+		})
+	}
+}
+
+func TestAddScriptInput(t *testing.T) {
+	pr := loadTestProtocol(t)
+	changeAddr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountKey := createRootKey().Derive(harden(1852)).Derive(harden(1815)).Derive(harden(0))
+	keys := []bip32.XPrv{accountKey.Derive(0).Derive(0), accountKey.Derive(0).Derive(1)}
+	keyHashes := []tx.AddrKeyHash{}
+	for _, key := range keys {
+		keyHash := key.Public().PublicKey().Hash()
+		keyHashes = append(keyHashes, keyHash[:])
+	}
+
+	// either key, from slot 100 until slot 500
+	script := tx.NativeScript{
+		Type: tx.ScriptAll,
+		Scripts: []tx.NativeScript{
+			{
+				Type: tx.ScriptAny,
+				Scripts: []tx.NativeScript{
+					{Type: tx.ScriptPubKey, KeyHash: keyHashes[0]},
+					{Type: tx.ScriptPubKey, KeyHash: keyHashes[1]},
+				},
+			},
+			{Type: tx.ScriptInvalidBefore, IntervalValue: 100},
+			{Type: tx.ScriptInvalidAfter, IntervalValue: 500},
+		},
+	}
+	scriptAddr, err := tx.NewScriptEnterpriseAddress(network.TestNet(), &script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo := tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(scriptAddr, 10000000))
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.SetTTL(1000)
+	if err := builder.AddScriptInput(utxo, script); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(100), builder.Tx().Body.ValidityIntervalStart)
+	assert.Equal(t, uint64(500), builder.Tx().Body.TTL)
+	assert.Equal(t, []tx.NativeScript{script}, builder.Tx().WitnessSet.Scripts)
+	assert.Equal(t, keyHashes[:1], builder.ScriptSigners())
+
+	builder.AddOutputs(tx.NewTxOutput(changeAddr, 2000000))
+	if err := builder.AddChangeIfNeeded(changeAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = builder.Build()
+	var missingSigners *tx.MissingSignersError
+	if assert.ErrorAs(t, err, &missingSigners) {
+		assert.Equal(t, keyHashes[:1], missingSigners.KeyHashes)
+	}
+
+	// any key satisfying the script can sign
+	builder.Sign(keys[1])
+	built, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := tx.Validate(&built, []*tx.TxInput{utxo}, pr, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, violations)
+
+	violations, err = tx.Validate(&built, []*tx.TxInput{utxo}, pr, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []tx.ViolationType{tx.ViolationOutsideValidityInterval}, violationTypes(violations))
+
+	// the script cannot be satisfied before slot 100
+	builder = tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.SetTTL(50)
+	assert.ErrorIs(t, builder.AddScriptInput(utxo, script), tx.ErrScriptNotSatisfiable)
+
+	// the input has to be locked by the script
+	other := tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, tx.NewTxOutput(changeAddr, 10000000))
+	assert.ErrorIs(t, builder.AddScriptInput(other, script), tx.ErrNotScriptAddress)
+	assert.Empty(t, builder.Tx().Body.Inputs)
+
+	// key 1 before slot 50, or keys 2 and 3: only the second alternative fits a transaction valid from slot 100
+	alternatives := tx.NativeScript{
+		Type: tx.ScriptAny,
+		Scripts: []tx.NativeScript{
+			{Type: tx.ScriptAll, Scripts: []tx.NativeScript{pubKeyScript(1), {Type: tx.ScriptInvalidAfter, IntervalValue: 50}}},
+			{Type: tx.ScriptAll, Scripts: []tx.NativeScript{pubKeyScript(2), pubKeyScript(3)}},
+		},
+	}
+	alternativesAddr, err := tx.NewScriptEnterpriseAddress(network.TestNet(), &alternatives)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder = tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.SetValidityIntervalStart(100)
+	if err := builder.AddScriptInput(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 2, tx.NewTxOutput(alternativesAddr, 10000000),
+	), alternatives); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []tx.AddrKeyHash{keyHash(2), keyHash(3)}, builder.ScriptSigners())
+	assert.Equal(t, uint64(100), builder.Tx().Body.ValidityIntervalStart)
+	assert.Equal(t, uint64(0), builder.Tx().Body.TTL)
+}
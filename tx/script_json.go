@@ -0,0 +1,120 @@
+package tx
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidNativeScript = errors.New("invalid native script")
+
+// nativeScriptJSON is the cardano-cli simple script JSON format.
+type nativeScriptJSON struct {
+	Type     string          `json:"type"`
+	KeyHash  string          `json:"keyHash,omitempty"`
+	Required *uint64         `json:"required,omitempty"`
+	Slot     *uint64         `json:"slot,omitempty"`
+	Scripts  *[]NativeScript `json:"scripts,omitempty"`
+}
+
+var nativeScriptJSONTypes = map[NativeScriptType]string{
+	ScriptPubKey:        "sig",
+	ScriptAll:           "all",
+	ScriptAny:           "any",
+	ScriptNofK:          "atLeast",
+	ScriptInvalidBefore: "after",
+	ScriptInvalidAfter:  "before",
+}
+
+// MarshalJSON implements json.Marshaler.
+// The script is encoded in the cardano-cli simple script format, e.g.
+// {"type":"all","scripts":[{"type":"sig","keyHash":"..."},{"type":"before","slot":1000}]}.
+func (ns NativeScript) MarshalJSON() ([]byte, error) {
+	if err := ns.validate(); err != nil {
+		return nil, err
+	}
+
+	script := nativeScriptJSON{Type: nativeScriptJSONTypes[ns.Type]}
+	scripts := append([]NativeScript{}, ns.Scripts...)
+	switch ns.Type {
+	case ScriptPubKey:
+		script.KeyHash = hex.EncodeToString(ns.KeyHash)
+	case ScriptAll, ScriptAny:
+		script.Scripts = &scripts
+	case ScriptNofK:
+		script.Required = &ns.N
+		script.Scripts = &scripts
+	case ScriptInvalidBefore, ScriptInvalidAfter:
+		script.Slot = &ns.IntervalValue
+	}
+	return json.Marshal(script)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It returns ErrInvalidNativeScript if the script is not a valid cardano-cli simple script.
+func (ns *NativeScript) UnmarshalJSON(data []byte) error {
+	var script nativeScriptJSON
+	if err := json.Unmarshal(data, &script); err != nil {
+		return err
+	}
+
+	*ns = NativeScript{}
+	switch script.Type {
+	case "sig":
+		keyHash, err := hex.DecodeString(script.KeyHash)
+		if err != nil {
+			return fmt.Errorf("%w: key hash %q is not hex encoded", ErrInvalidNativeScript, script.KeyHash)
+		}
+		ns.Type = ScriptPubKey
+		ns.KeyHash = keyHash
+	case "all", "any", "atLeast":
+		if script.Scripts == nil {
+			return fmt.Errorf("%w: %q script without scripts", ErrInvalidNativeScript, script.Type)
+		}
+		ns.Type = ScriptAll
+		if script.Type == "any" {
+			ns.Type = ScriptAny
+		}
+		if script.Type == "atLeast" {
+			if script.Required == nil {
+				return fmt.Errorf("%w: atLeast script without required", ErrInvalidNativeScript)
+			}
+			ns.Type = ScriptNofK
+			ns.N = *script.Required
+		}
+		ns.Scripts = *script.Scripts
+	case "after", "before":
+		if script.Slot == nil {
+			return fmt.Errorf("%w: %q script without slot", ErrInvalidNativeScript, script.Type)
+		}
+		ns.Type = ScriptInvalidBefore
+		if script.Type == "before" {
+			ns.Type = ScriptInvalidAfter
+		}
+		ns.IntervalValue = *script.Slot
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidNativeScript, script.Type)
+	}
+
+	return ns.validate()
+}
+
+// validate checks that key hashes are 28 bytes and that ScriptNofK requires at most its number of scripts.
+// Sub scripts are validated when they are encoded or decoded.
+func (ns *NativeScript) validate() error {
+	switch ns.Type {
+	case ScriptPubKey:
+		if len(ns.KeyHash) != 28 {
+			return fmt.Errorf("%w: key hash %x is %d bytes, expected 28", ErrInvalidNativeScript, ns.KeyHash, len(ns.KeyHash))
+		}
+	case ScriptNofK:
+		if ns.N > uint64(len(ns.Scripts)) {
+			return fmt.Errorf("%w: atLeast %d of %d scripts", ErrInvalidNativeScript, ns.N, len(ns.Scripts))
+		}
+	case ScriptAll, ScriptAny, ScriptInvalidBefore, ScriptInvalidAfter:
+	default:
+		return fmt.Errorf("%w: unknown type %d", ErrInvalidNativeScript, ns.Type)
+	}
+	return nil
+}
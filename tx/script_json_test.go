@@ -0,0 +1,108 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNativeScriptJSON(t *testing.T) {
+	scriptJSON := `{
+		"type": "all",
+		"scripts": [
+			{"type": "sig", "keyHash": "01010101010101010101010101010101010101010101010101010101"},
+			{"type": "atLeast", "required": 1, "scripts": [
+				{"type": "sig", "keyHash": "02020202020202020202020202020202020202020202020202020202"},
+				{"type": "any", "scripts": []}
+			]},
+			{"type": "after", "slot": 100},
+			{"type": "before", "slot": 200}
+		]
+	}`
+	expected := tx.NativeScript{
+		Type: tx.ScriptAll,
+		Scripts: []tx.NativeScript{
+			pubKeyScript(1),
+			{
+				Type: tx.ScriptNofK,
+				N:    1,
+				Scripts: []tx.NativeScript{
+					pubKeyScript(2),
+					{Type: tx.ScriptAny, Scripts: []tx.NativeScript{}},
+				},
+			},
+			{Type: tx.ScriptInvalidBefore, IntervalValue: 100},
+			{Type: tx.ScriptInvalidAfter, IntervalValue: 200},
+		},
+	}
+
+	var script tx.NativeScript
+	if err := json.Unmarshal([]byte(scriptJSON), &script); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, script)
+
+	data, err := json.Marshal(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, scriptJSON, string(data))
+
+	// the policy id is the same as for the script built in code
+	hash, err := script.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHash, err := expected.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expectedHash, hash)
+
+	// policy id of the multisig script of addr_test1wqklxqkgu755t8lxv6haj6aymqhzuljxc8wmpc546ulslks5tr7ya,
+	// the script address created with cardano-cli that the multisig transaction tests spend from
+	multisigJSON := `{
+		"type": "atLeast",
+		"required": 2,
+		"scripts": [
+			{"type": "sig", "keyHash": "d8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1"},
+			{"type": "sig", "keyHash": "86b45d41aee0a41bc3c099d3108f251b4318a28f883e19abefb618c8"},
+			{"type": "sig", "keyHash": "159bf228e41bc1e2b5fd1f347627db28111848f6b044ab1dc8bf5f57"}
+		]
+	}`
+	var multisig tx.NativeScript
+	if err := json.Unmarshal([]byte(multisigJSON), &multisig); err != nil {
+		t.Fatal(err)
+	}
+	multisigHash, err := multisig.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "2df302c8e7a9459fe666afd96ba4d82e2e7e46c1ddb0e295d73f0fda", hex.EncodeToString(multisigHash))
+
+	invalid := []struct {
+		description string
+		json        string
+	}{
+		{"short key hash", `{"type": "sig", "keyHash": "0101"}`},
+		{"non hex key hash", `{"type": "sig", "keyHash": "zz"}`},
+		{"required exceeds scripts", `{"type": "atLeast", "required": 2, "scripts": [{"type": "after", "slot": 1}]}`},
+		{"missing required", `{"type": "atLeast", "scripts": []}`},
+		{"missing scripts", `{"type": "all"}`},
+		{"missing slot", `{"type": "before"}`},
+		{"unknown type", `{"type": "plutus"}`},
+		{"invalid sub script", `{"type": "any", "scripts": [{"type": "sig", "keyHash": ""}]}`},
+	}
+	for _, sc := range invalid {
+		t.Run(sc.description, func(t *testing.T) {
+			var script tx.NativeScript
+			assert.ErrorIs(t, json.Unmarshal([]byte(sc.json), &script), tx.ErrInvalidNativeScript)
+		})
+	}
+
+	_, err = json.Marshal(tx.NativeScript{Type: tx.ScriptNofK, N: 1})
+	assert.ErrorIs(t, err, tx.ErrInvalidNativeScript)
+}
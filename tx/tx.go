@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/fxamacker/cbor/v2"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
 	"github.com/milos-ethernal/go-cardano-serialization/fees"
 	"golang.org/x/crypto/blake2b"
 )
@@ -27,6 +28,24 @@ func NewTx() *Tx {
 	}
 }
 
+// NewTxFromBytes returns a pointer to a Transaction decoded from its cbor encoding.
+func NewTxFromBytes(data []byte) (*Tx, error) {
+	t := &Tx{}
+	if err := cborDec.Unmarshal(data, t); err != nil {
+		return nil, fmt.Errorf("cannot deserialize transaction: %w", err)
+	}
+	return t, nil
+}
+
+// NewTxFromHex returns a pointer to a Transaction decoded from its hex encoded cbor (cborHex).
+func NewTxFromHex(txHex string) (*Tx, error) {
+	data, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, err
+	}
+	return NewTxFromBytes(data)
+}
+
 // Bytes returns a slice of cbor marshalled bytes
 func (t *Tx) Bytes() ([]byte, error) {
 	if err := t.CalculateAuxiliaryDataHash(); err != nil {
@@ -60,9 +79,10 @@ func (t *Tx) Hash() ([32]byte, error) {
 	return txHash, nil
 }
 
-// Fee returns the fee(in lovelaces) required by the transaction from the linear formula
-// fee = txFeeFixed + txFeePerByte*tx_len_in_bytes
-func (t *Tx) Fee(lfee *fees.LinearFee) (uint, error) {
+// Fee returns the fee(in lovelaces) required by the transaction from the size of the transaction,
+// the execution units of its redeemers and the size of the reference scripts of its inputs.
+// With a fees.LinearFee this is the linear formula fee = txFeeFixed + txFeePerByte*tx_len_in_bytes
+func (t *Tx) Fee(calc fees.Calculator) (uint, error) {
 	if err := t.CalculateAuxiliaryDataHash(); err != nil {
 		return 0, err
 	}
@@ -71,11 +91,130 @@ func (t *Tx) Fee(lfee *fees.LinearFee) (uint, error) {
 		return 0, err
 	}
 	txBodyLen := len(txCbor)
-	fee := lfee.TxFeeFixed + lfee.TxFeePerByte*uint(txBodyLen)
+	exUnits := ExUnits{}
+	if t.WitnessSet != nil {
+		exUnits = t.WitnessSet.Redeemers.ExUnits()
+	}
+	fee := calc.MinFee(uint(txBodyLen), exUnits.Mem, exUnits.Steps, t.referenceScriptSize())
 
 	return fee, nil
 }
 
+// referenceScriptInputs returns the spent and reference inputs carrying a reference script.
+func (t *Tx) referenceScriptInputs() []*TxInput {
+	inputs := []*TxInput{}
+	for _, set := range [][]*TxInput{t.Body.Inputs, t.Body.ReferenceInputs} {
+		for _, input := range set {
+			if input.ScriptRef != nil {
+				inputs = append(inputs, input)
+			}
+		}
+	}
+	return inputs
+}
+
+// referenceScriptSize returns the total size of the reference scripts of the resolved inputs.
+func (t *Tx) referenceScriptSize() uint {
+	size := 0
+	for _, input := range t.referenceScriptInputs() {
+		// reference scripts of resolved inputs were encoded when they were created
+		n, _ := input.ScriptRef.Size()
+		size += n
+	}
+	return uint(size)
+}
+
+// scriptHashes returns the hashes of the native and Plutus scripts of the witness set
+// and of the reference scripts of the resolved inputs.
+func (t *Tx) scriptHashes() (map[string]bool, error) {
+	scripts := map[string]bool{}
+	for _, script := range t.WitnessSet.Scripts {
+		hash, err := script.Hash()
+		if err != nil {
+			return nil, err
+		}
+		scripts[string(hash)] = true
+	}
+	for _, script := range t.WitnessSet.PlutusScripts() {
+		hash, err := script.Hash()
+		if err != nil {
+			return nil, err
+		}
+		scripts[string(hash)] = true
+	}
+	for _, input := range t.referenceScriptInputs() {
+		hash, err := input.ScriptRef.hash()
+		if err != nil {
+			return nil, err
+		}
+		scripts[string(hash)] = true
+	}
+	return scripts, nil
+}
+
+// SignWitness returns the vkey witness of the key over the transaction body hash.
+func (t *Tx) SignWitness(xprv bip32.XPrv) (VKeyWitness, error) {
+	hash, err := t.Hash()
+	if err != nil {
+		return VKeyWitness{}, err
+	}
+	signature := xprv.Sign(hash[:])
+
+	return NewVKeyWitness(xprv.Public().PublicKey(), signature[:]), nil
+}
+
+// SignWitnessSet returns a standalone witness set holding the vkey witnesses of the keys over the transaction
+// body hash, e.g. for a party of a multisig transaction to send to the coordinator, see AddWitnesses.
+func (t *Tx) SignWitnessSet(xprvs ...bip32.XPrv) (*WitnessSet, error) {
+	witnesses := []VKeyWitness{}
+	for _, xprv := range xprvs {
+		witness, err := t.SignWitness(xprv)
+		if err != nil {
+			return nil, err
+		}
+		witnesses = append(witnesses, witness)
+	}
+	return NewTXWitnessSet(nil, witnesses), nil
+}
+
+// AddWitnesses merges the witness sets, e.g. produced by the parties of a multisig transaction,
+// into the witness set of the transaction. Duplicate witnesses and scripts are added once.
+// ErrInvalidWitness is returned, and nothing is added, if a signature is not valid for the transaction body.
+func (t *Tx) AddWitnesses(witnessSets ...*WitnessSet) error {
+	hash, err := t.Hash()
+	if err != nil {
+		return err
+	}
+	for _, witnessSet := range witnessSets {
+		if err := witnessSet.verify(hash); err != nil {
+			return err
+		}
+	}
+
+	if t.WitnessSet == nil {
+		t.WitnessSet = NewTXWitnessSet(nil, nil)
+	}
+	for _, witnessSet := range witnessSets {
+		if err := t.WitnessSet.Merge(witnessSet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyWitnesses returns ErrInvalidWitness if a signature of the vkey or bootstrap witnesses of the transaction
+// is not valid for the transaction body. It does not check that every required key signed, see Validate.
+func (t *Tx) VerifyWitnesses() error {
+	hash, err := t.Hash()
+	if err != nil {
+		return err
+	}
+	if t.WitnessSet == nil {
+		return nil
+	}
+	return t.WitnessSet.verify(hash)
+}
+
 // SetFee sets the fee
 func (t *Tx) SetFee(fee uint) {
 	t.Body.Fee = uint64(fee)
@@ -6,13 +6,31 @@ import (
 	"github.com/fxamacker/cbor/v2"
 )
 
-// TxBody contains the inputs, outputs, fee and titme to live for the transaction.
+// TxBody contains the inputs, outputs, fee, validity interval, certificates, withdrawals, minted tokens,
+// script data hash, collateral, required signers, reference inputs, votes, proposals and treasury donation
+// for the transaction.
 type TxBody struct {
-	Inputs            []*TxInput  `cbor:"0,keyasint"`
-	Outputs           []*TxOutput `cbor:"1,keyasint"`
-	Fee               uint64      `cbor:"2,keyasint"`
-	TTL               uint32      `cbor:"3,keyasint,omitempty"`
-	AuxiliaryDataHash []byte      `cbor:"7,keyasint,omitempty"`
+	Inputs                []*TxInput          `cbor:"0,keyasint"`
+	Outputs               []*TxOutput         `cbor:"1,keyasint"`
+	Fee                   uint64              `cbor:"2,keyasint"`
+	TTL                   uint64              `cbor:"3,keyasint,omitempty"`
+	Certificates          []Certificate       `cbor:"4,keyasint,omitempty"`
+	Withdrawals           Withdrawals         `cbor:"5,keyasint,omitempty"`
+	AuxiliaryDataHash     []byte              `cbor:"7,keyasint,omitempty"`
+	ValidityIntervalStart uint64              `cbor:"8,keyasint,omitempty"`
+	Mint                  Mint                `cbor:"9,keyasint,omitempty"`
+	ScriptDataHash        []byte              `cbor:"11,keyasint,omitempty"`
+	Collateral            []*TxInput          `cbor:"13,keyasint,omitempty"`
+	RequiredSigners       []Hash28            `cbor:"14,keyasint,omitempty"`
+	CollateralReturn      *TxOutput           `cbor:"16,keyasint,omitempty"`
+	TotalCollateral       uint64              `cbor:"17,keyasint,omitempty"`
+	ReferenceInputs       []*TxInput          `cbor:"18,keyasint,omitempty"`
+	VotingProcedures      VotingProcedures    `cbor:"19,keyasint,omitempty"`
+	ProposalProcedures    []ProposalProcedure `cbor:"20,keyasint,omitempty"`
+	CurrentTreasuryValue  uint64              `cbor:"21,keyasint,omitempty"`
+	Donation              uint64              `cbor:"22,keyasint,omitempty"`
+
+	raw rawBytes
 }
 
 // NewTxBody returns a pointer to a new transaction body.
@@ -23,6 +41,40 @@ func NewTxBody() *TxBody {
 	}
 }
 
+// MarshalCBOR implements cbor.Marshaler.
+// A decoded body that has not been modified is returned with its original bytes.
+// The body is encoded canonically: the keys of its map fields (mint, withdrawals and voting procedures)
+// would otherwise be encoded in random order, changing the body hash of the same transaction and failing
+// the detection of modified decoded bodies. Bodies without map fields are encoded as by cbor.Marshal.
+func (b *TxBody) MarshalCBOR() ([]byte, error) {
+	type txBody TxBody
+
+	enc, err := cborEnc.Marshal(txBody(*b))
+	if err != nil {
+		return nil, err
+	}
+	return b.raw.bytes(enc), nil
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (b *TxBody) UnmarshalCBOR(data []byte) error {
+	type txBody TxBody
+
+	var body txBody
+	if err := cborDec.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	*b = TxBody(body)
+
+	enc, err := cborEnc.Marshal(body)
+	if err != nil {
+		return err
+	}
+	b.raw.keep(data, enc)
+
+	return nil
+}
+
 // Bytes returns a slice of cbor Marshalled bytes.
 func (b *TxBody) Bytes() ([]byte, error) {
 	bytes, err := cbor.Marshal(b)
@@ -1,17 +1,79 @@
 package tx
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/milos-ethernal/go-cardano-serialization/address"
 	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/crypto"
 	"github.com/milos-ethernal/go-cardano-serialization/fees"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
 	"github.com/milos-ethernal/go-cardano-serialization/protocol"
 )
 
+var (
+	ErrMissingWitness   = errors.New("missing witness")
+	ErrNotScriptAddress = errors.New("address payment credential is not a script")
+	ErrNoCollateral     = errors.New("not enough collateral")
+)
+
+// MissingSignersError is returned by Build when no signing key was supplied for key hashes
+// that must sign the transaction, or no script for script hashes that must witness it.
+type MissingSignersError struct {
+	// KeyHashes are the missing key hashes, sorted.
+	KeyHashes []Hash28
+	// ScriptHashes are the missing script hashes, sorted.
+	ScriptHashes []Hash28
+}
+
+func (e *MissingSignersError) Error() string {
+	joinHashes := func(hashes []Hash28) string {
+		encoded := make([]string, len(hashes))
+		for i, hash := range hashes {
+			encoded[i] = hex.EncodeToString(hash)
+		}
+		return strings.Join(encoded, ", ")
+	}
+
+	missing := []string{}
+	if len(e.KeyHashes) > 0 {
+		missing = append(missing, "no signing key for key hashes "+joinHashes(e.KeyHashes))
+	}
+	if len(e.ScriptHashes) > 0 {
+		missing = append(missing, "no script for script hashes "+joinHashes(e.ScriptHashes))
+	}
+	return fmt.Sprintf("%v: %s", ErrMissingWitness, strings.Join(missing, "; "))
+}
+
+func (e *MissingSignersError) Unwrap() error {
+	return ErrMissingWitness
+}
+
 // TxBuilder - used to create, validate and sign transactions.
 type TxBuilder struct {
 	tx       *Tx
 	xprvs    []bip32.XPrv
 	protocol protocol.Protocol
+	// spends are the redeemers of Plutus script inputs, indexed once the inputs are known.
+	spends    []scriptSpend
+	redeemers Redeemers
+	// collateral is the UTxO set collateral is selected from, with change returned to collateralReturn.
+	collateral       []*TxInput
+	collateralReturn address.Address
+	// lockScripts are the native scripts locking inputs added by AddScriptInput, satisfied by lockSatisfaction.
+	lockScripts      []NativeScript
+	lockSatisfaction ScriptSatisfaction
+}
+
+type scriptSpend struct {
+	input    *TxInput
+	redeemer PlutusData
+	exUnits  ExUnits
 }
 
 // Sign adds a private key to create signature for witness
@@ -20,112 +82,850 @@ func (tb *TxBuilder) Sign(xprv bip32.XPrv) {
 }
 
 // Build creates hash of transaction, signs the hash using supplied witnesses and adds them to the transaction.
+// It returns a *MissingSignersError if no signing key was supplied for a required signer or key locked input,
+// or no script for a script credential.
 func (tb *TxBuilder) Build() (tx Tx, err error) {
 	if tx.WitnessSet != nil {
 		return *tb.tx, nil
 	}
 
+	if err := tb.setScriptData(); err != nil {
+		return tx, err
+	}
+
+	if err := tb.setCollateral(); err != nil {
+		return tx, err
+	}
+
+	if err := tb.checkWitnesses(); err != nil {
+		return tx, err
+	}
+
 	hash, err := tb.tx.Hash()
 	if err != nil {
 		return tx, err
 	}
 
+	// keys spending from Byron addresses sign with bootstrap witnesses
+	byronKeys := map[int]bool{}
+	bootstrapWitnesses := []BootstrapWitness{}
+	for _, addr := range tb.byronInputs() {
+		i, _ := tb.byronSigner(addr)
+		prv := tb.xprvs[i]
+		signature := prv.Sign(hash[:])
+
+		witness, err := NewBootstrapWitness(prv.Public().PublicKey(), signature[:], prv.ChainCode(), addr)
+		if err != nil {
+			return tx, err
+		}
+		bootstrapWitnesses = append(bootstrapWitnesses, witness)
+		byronKeys[i] = true
+	}
+
 	txKeys := []VKeyWitness{}
-	for _, prv := range tb.xprvs {
+	for i, prv := range tb.xprvs {
+		if byronKeys[i] {
+			continue
+		}
 		publicKey := prv.Public().PublicKey()
 		signature := prv.Sign(hash[:])
 
 		txKeys = append(txKeys, NewVKeyWitness(publicKey, signature[:]))
 	}
 
-	tb.tx.WitnessSet = NewTXWitnessSet(
-		[]NativeScript{}, txKeys,
-	)
+	if tb.tx.WitnessSet == nil {
+		tb.tx.WitnessSet = NewTXWitnessSet([]NativeScript{}, txKeys)
+	}
+	tb.tx.WitnessSet.Witnesses = txKeys
+	tb.tx.WitnessSet.BootstrapWitnesses = bootstrapWitnesses
 
 	return *tb.tx, nil
 }
 
+// BuildUnsigned returns the transaction without witnesses, for the keys to sign separately,
+// e.g. by the parties of a multisig transaction. See Tx.SignWitnessSet and Tx.AddWitnesses.
+func (tb *TxBuilder) BuildUnsigned() (tx Tx, err error) {
+	if err := tb.setScriptData(); err != nil {
+		return tx, err
+	}
+
+	if err := tb.setCollateral(); err != nil {
+		return tx, err
+	}
+
+	return *tb.tx, nil
+}
+
+// BuildUnsignedPackage returns the unsigned transaction bundled with its resolved inputs, collateral and
+// reference inputs, the network and the protocol parameters, for an offline signer to review and sign.
+// ErrUnresolvedInput is returned if an input has no address, e.g. an input created with NewTxInput.
+func (tb *TxBuilder) BuildUnsignedPackage(net *network.NetworkInfo) (*UnsignedTxPackage, error) {
+	tx, err := tb.BuildUnsigned()
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := []*TxInput{}
+	for _, set := range [][]*TxInput{tx.Body.Inputs, tx.Body.Collateral, tx.Body.ReferenceInputs} {
+		for _, input := range set {
+			if input.Address == nil {
+				return nil, fmt.Errorf("%w: %x#%d has no address", ErrUnresolvedInput, input.TxHash, input.Index)
+			}
+		}
+		utxos = append(utxos, set...)
+	}
+	return NewUnsignedTxPackage(&tx, utxos, net, tb.protocol), nil
+}
+
 // Tx returns a pointer to the transaction
 func (tb *TxBuilder) Tx() (tx *Tx) {
 	return tb.tx
 }
 
 // AddChangeIfNeeded calculates the excess change from UTXO inputs - outputs and adds it to the transaction body.
-func (tb *TxBuilder) AddChangeIfNeeded(addr address.Address) {
-	totalI, totalO := tb.GetTotalInputOutputs()
+// Native tokens that are not sent to any output are returned in the change output as well.
+func (tb *TxBuilder) AddChangeIfNeeded(addr address.Address) error {
+	if err := tb.setScriptData(); err != nil {
+		return err
+	}
+
+	consumed, produced := tb.balance()
 
-	change := totalI - totalO - uint(tb.tx.Body.Fee)
+	change, err := consumed.Sub(produced.Add(NewValue(uint(tb.tx.Body.Fee))))
+	if err != nil {
+		return err
+	}
 	tb.tx.AddOutputs(
-		NewTxOutput(
+		NewTxOutputWithValue(
 			addr,
 			change,
 		),
 	)
 
-	// change is amount in utxo minus outputs minus fee
-	tb.tx.SetFee(tb.MinFee())
+	changeOutput := tb.tx.Body.Outputs[len(tb.tx.Body.Outputs)-1]
+
+	// change is amount in utxo minus outputs minus fee, and the fee depends on the change
+	for i := 0; i < maxFeeIterations; i++ {
+		if err := tb.setCollateral(); err != nil {
+			return err
+		}
+		fee, err := tb.MinFee()
+		if err != nil {
+			return err
+		}
+		if i > 0 && uint64(fee) == tb.tx.Body.Fee {
+			break
+		}
+		tb.tx.SetFee(fee)
+
+		change, err = consumed.Sub(produced.Add(NewValue(fee)))
+		if err != nil {
+			return err
+		}
+		changeOutput.Value = *change
+	}
 
-	// update change amount
-	tb.tx.Body.Outputs[len(tb.tx.Body.Outputs)-1].Amount = totalI - totalO - uint(tb.tx.Body.Fee)
+	return nil
 }
 
 // SetTTL sets the time to live for the transaction.
-func (tb *TxBuilder) SetTTL(ttl uint32) {
+// The transaction is valid up to, but not including, slot ttl.
+func (tb *TxBuilder) SetTTL(ttl uint64) {
 	tb.tx.Body.TTL = ttl
 }
 
+// SetValidityIntervalStart sets the slot from which the transaction is valid.
+func (tb *TxBuilder) SetValidityIntervalStart(slot uint64) {
+	tb.tx.Body.ValidityIntervalStart = slot
+}
+
+// SetDonation sets the lovelace donated to the treasury.
+// The donation is included when balancing the transaction.
+func (tb *TxBuilder) SetDonation(amount uint) {
+	tb.tx.Body.Donation = uint64(amount)
+}
+
+// SetCurrentTreasuryValue sets the current treasury value the transaction asserts,
+// which makes the transaction valid only while the treasury holds exactly that amount.
+func (tb *TxBuilder) SetCurrentTreasuryValue(amount uint) {
+	tb.tx.Body.CurrentTreasuryValue = uint64(amount)
+}
+
+// GetTotalInputOutputs returns the total lovelace of the inputs and outputs.
 func (tb TxBuilder) GetTotalInputOutputs() (inputs, outputs uint) {
+	totalI, totalO := tb.GetTotalInputOutputValues()
+
+	return totalI.Amount, totalO.Amount
+}
+
+// GetTotalInputOutputValues returns the total value (lovelace and tokens) of the inputs and outputs.
+func (tb TxBuilder) GetTotalInputOutputValues() (inputs, outputs *Value) {
+	inputs, outputs = NewValue(0), NewValue(0)
 	for _, inp := range tb.tx.Body.Inputs {
-		inputs += inp.Amount
+		inputs = inputs.Add(&inp.Value)
 	}
 	for _, out := range tb.tx.Body.Outputs {
-		outputs += uint(out.Amount)
+		outputs = outputs.Add(&out.Value)
 	}
 
 	return
 }
 
+// balance returns the value consumed (inputs, withdrawals, minted tokens and deposit refunds) and produced
+// (outputs, burned tokens, deposits and donation) by the transaction, excluding the fee.
+func (tb TxBuilder) balance() (consumed, produced *Value) {
+	consumed, produced = tb.GetTotalInputOutputValues()
+	consumed.Amount += tb.tx.Body.Withdrawals.Total()
+	consumed = consumed.Add(tb.tx.Body.Mint.Minted())
+	produced = produced.Add(tb.tx.Body.Mint.Burned())
+
+	for _, cert := range tb.tx.Body.Certificates {
+		deposit, refund := cert.deposits(tb.protocol)
+		consumed.Amount += refund
+		produced.Amount += deposit
+	}
+	for _, proposal := range tb.tx.Body.ProposalProcedures {
+		produced.Amount += proposal.Deposit
+	}
+	produced.Amount += uint(tb.tx.Body.Donation)
+
+	return
+}
+
+// AddCertificates adds certificates to the transaction body.
+// Zero deposits of Conway certificates are set from the protocol parameters.
+// Deposits and refunds of the certificates are included when balancing the transaction.
+func (tb *TxBuilder) AddCertificates(certs ...Certificate) {
+	for _, cert := range certs {
+		cert.setDeposit(tb.protocol)
+		tb.tx.Body.Certificates = append(tb.tx.Body.Certificates, cert)
+	}
+}
+
+// AddWithdrawal withdraws amount lovelace of rewards from the reward address.
+// The withdrawn amount is included when balancing the transaction.
+func (tb *TxBuilder) AddWithdrawal(addr *address.RewardAddress, amount uint) {
+	if tb.tx.Body.Withdrawals == nil {
+		tb.tx.Body.Withdrawals = Withdrawals{}
+	}
+	tb.tx.Body.Withdrawals[NewRewardAccount(addr)] = amount
+}
+
+// AddVote adds the vote of the voter on the governance action, with an optional anchor to its rationale.
+// The voter's key or script has to witness the transaction.
+func (tb *TxBuilder) AddVote(voter Voter, actionID GovActionID, vote Vote, anchor *Anchor) {
+	if tb.tx.Body.VotingProcedures == nil {
+		tb.tx.Body.VotingProcedures = VotingProcedures{}
+	}
+	if tb.tx.Body.VotingProcedures[voter] == nil {
+		tb.tx.Body.VotingProcedures[voter] = map[GovActionID]VotingProcedure{}
+	}
+	tb.tx.Body.VotingProcedures[voter][actionID] = VotingProcedure{Vote: vote, Anchor: anchor}
+}
+
+// AddProposal proposes the governance action, with an anchor to its rationale.
+// The govActionDeposit from the protocol parameters is included when balancing the transaction,
+// and returned to the reward address once the action is enacted or expires.
+func (tb *TxBuilder) AddProposal(rewardAddr *address.RewardAddress, action GovAction, anchor Anchor) {
+	tb.tx.Body.ProposalProcedures = append(tb.tx.Body.ProposalProcedures, ProposalProcedure{
+		Deposit:       tb.protocol.GovActionDeposit,
+		RewardAccount: NewRewardAccount(rewardAddr),
+		GovAction:     action,
+		Anchor:        anchor,
+	})
+}
+
+// requiredCredentials returns the credentials that must witness the transaction,
+// either by a signature of the key or by including the script.
+// An error is returned if a withdrawal is not from a reward address.
+func (tb TxBuilder) requiredCredentials() ([]*address.StakeCredential, error) {
+	var creds []*address.StakeCredential
+	for _, cert := range tb.tx.Body.Certificates {
+		if cred := cert.witnessCredential(); cred != nil {
+			creds = append(creds, cred)
+		}
+	}
+	for account := range tb.tx.Body.Withdrawals {
+		addr, err := account.Address()
+		if err != nil {
+			return nil, fmt.Errorf("invalid withdrawal reward account %x: %w", []byte(account), err)
+		}
+		creds = append(creds, &addr.Stake)
+	}
+	for _, set := range [][]*TxInput{tb.tx.Body.Inputs, tb.tx.Body.Collateral} {
+		for _, input := range set {
+			if input.Address == nil {
+				continue
+			}
+			// script locked inputs are witnessed by the script added with the input
+			if cred := paymentCredential(input.Address); cred != nil && cred.Kind == address.KeyStakeCredentialType {
+				creds = append(creds, cred)
+			}
+		}
+	}
+	for voter := range tb.tx.Body.VotingProcedures {
+		creds = append(creds, voter.credential())
+	}
+	for _, keyHash := range tb.tx.Body.RequiredSigners {
+		creds = append(creds, address.NewKeyStakeCredential(keyHash))
+	}
+
+	return creds, nil
+}
+
+// checkWitnesses verifies that a signing key or script was supplied for every required credential.
+// Scripts may be native or Plutus scripts of the witness set, or reference scripts of the inputs.
+func (tb TxBuilder) checkWitnesses() error {
+	// keys of Byron inputs sign with bootstrap witnesses, whose key hash is the root of the address
+	byronKeys := map[int]bool{}
+	signers := map[string]bool{}
+	for _, addr := range tb.byronInputs() {
+		if i, ok := tb.byronSigner(addr); ok {
+			byronKeys[i] = true
+			signers[string(addr.Hash)] = true
+		}
+	}
+	for i, prv := range tb.xprvs {
+		if byronKeys[i] {
+			continue
+		}
+		keyHash := prv.Public().PublicKey().Hash()
+		signers[string(keyHash[:])] = true
+	}
+
+	scripts, err := tb.tx.scriptHashes()
+	if err != nil {
+		return err
+	}
+
+	creds, err := tb.requiredCredentials()
+	if err != nil {
+		return err
+	}
+
+	missing := map[string]bool{}
+	missingScripts := map[string]bool{}
+	for _, cred := range creds {
+		switch cred.Kind {
+		case address.KeyStakeCredentialType:
+			if !signers[string(cred.Payload)] {
+				missing[string(cred.Payload)] = true
+			}
+		case address.ScriptStakeCredentialType:
+			if !scripts[string(cred.Payload)] {
+				missingScripts[string(cred.Payload)] = true
+			}
+		}
+	}
+
+	for _, addr := range tb.byronInputs() {
+		if _, ok := tb.byronSigner(addr); !ok {
+			return fmt.Errorf("%w: no signing key for byron address %s", ErrMissingWitness, addr)
+		}
+	}
+
+	keyHashes := []AddrKeyHash{}
+	for keyHash := range signers {
+		keyHashes = append(keyHashes, AddrKeyHash(keyHash))
+	}
+	for _, script := range tb.lockScripts {
+		if script.Evaluate(keyHashes, tb.tx.Body.ValidityIntervalStart, tb.tx.Body.TTL) {
+			continue
+		}
+		// report the recorded signers of the script that did not sign
+		for _, keyHash := range script.keyHashes() {
+			for _, signer := range tb.lockSatisfaction.KeyHashes {
+				if bytes.Equal(keyHash, signer) && !signers[string(keyHash)] {
+					missing[string(keyHash)] = true
+				}
+			}
+		}
+		if len(missing) == 0 {
+			hash, _ := script.Hash()
+			return fmt.Errorf("%w: script %x is not satisfied within the validity interval", ErrScriptNotSatisfiable, hash)
+		}
+	}
+
+	if len(missing) > 0 || len(missingScripts) > 0 {
+		return &MissingSignersError{
+			KeyHashes:    sortedHashes(missing),
+			ScriptHashes: sortedHashes(missingScripts),
+		}
+	}
+
+	return nil
+}
+
+// sortedHashes returns the hashes of the set, sorted.
+func sortedHashes(set map[string]bool) []Hash28 {
+	var hashes []Hash28
+	for hash := range set {
+		hashes = append(hashes, Hash28(hash))
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i], hashes[j]) < 0
+	})
+	return hashes
+}
+
+// byronInputs returns the distinct Byron addresses of the inputs and collateral inputs.
+func (tb TxBuilder) byronInputs() []*address.ByronAddress {
+	addrs := []*address.ByronAddress{}
+	seen := map[string]bool{}
+	for _, set := range [][]*TxInput{tb.tx.Body.Inputs, tb.tx.Body.Collateral} {
+		for _, input := range set {
+			addr, ok := input.Address.(*address.ByronAddress)
+			if !ok || seen[string(addr.Bytes())] {
+				continue
+			}
+			seen[string(addr.Bytes())] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// byronSigner returns the index of the signing key of the Byron address, or false if it was not supplied.
+func (tb TxBuilder) byronSigner(addr *address.ByronAddress) (int, bool) {
+	for i, prv := range tb.xprvs {
+		keyAddr, err := address.NewByronAddress(prv.Public().PublicKey(), prv.ChainCode(), addr.Attributes)
+		if err == nil && bytes.Equal(keyAddr.Hash, addr.Hash) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// AddRequiredSigners adds key hashes that must sign the transaction, e.g. signers required by a Plutus validator.
+// Build fails unless a signing key was supplied for each of them.
+func (tb *TxBuilder) AddRequiredSigners(keyHashes ...AddrKeyHash) {
+	for _, keyHash := range keyHashes {
+		found := false
+		for _, signer := range tb.tx.Body.RequiredSigners {
+			if bytes.Equal(signer, keyHash) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tb.tx.Body.RequiredSigners = append(tb.tx.Body.RequiredSigners, keyHash)
+		}
+	}
+}
+
+// AddScriptInput adds the input locked by the native script, and adds the script to the witness set.
+// The validity interval of the transaction is narrowed to the interval required by the script, and the
+// fewest keys satisfying the script are recorded, see ScriptSigners.
+// ErrNotScriptAddress is returned if the input is not locked by the script, and ErrScriptNotSatisfiable
+// if the script cannot be satisfied within the validity interval of the transaction.
+func (tb *TxBuilder) AddScriptInput(utxo *TxInput, script NativeScript) error {
+	hash, err := script.Hash()
+	if err != nil {
+		return err
+	}
+	if utxo.Address == nil {
+		return fmt.Errorf("%w: input %x#%d has no address", ErrNotScriptAddress, utxo.TxHash, utxo.Index)
+	}
+	payment := paymentCredential(utxo.Address)
+	if payment == nil || payment.Kind != address.ScriptStakeCredentialType || !bytes.Equal(payment.Payload, hash) {
+		return fmt.Errorf("%w: %s is not locked by script %x", ErrNotScriptAddress, utxo.Address.String(), hash)
+	}
+
+	// the script is satisfied within the current validity interval, sharing the signers of the other scripts
+	current := tb.lockSatisfaction
+	current.ValidityStart = tb.tx.Body.ValidityIntervalStart
+	current.TTL = tb.tx.Body.TTL
+	merged, ok := script.minimalSatisfaction(current)
+	if !ok {
+		return fmt.Errorf("%w: script %x within slots %d to %d", ErrScriptNotSatisfiable, hash, current.ValidityStart, current.TTL)
+	}
+
+	if err := tb.addNativeScript(script); err != nil {
+		return err
+	}
+	tb.AddInputs(utxo)
+	tb.tx.Body.ValidityIntervalStart = merged.ValidityStart
+	tb.tx.Body.TTL = merged.TTL
+	tb.lockScripts = append(tb.lockScripts, script)
+	tb.lockSatisfaction = merged
+
+	return nil
+}
+
+// ScriptSigners returns the fewest key hashes that have to sign the transaction to satisfy the native
+// scripts of the inputs added by AddScriptInput, sorted.
+// Build accepts any other set of signing keys satisfying the scripts.
+func (tb TxBuilder) ScriptSigners() []AddrKeyHash {
+	return append([]AddrKeyHash{}, tb.lockSatisfaction.KeyHashes...)
+}
+
+// AddNativeScripts adds native scripts to the witness set, e.g. scripts witnessing
+// a script stake credential used by a certificate or withdrawal.
+func (tb *TxBuilder) AddNativeScripts(scripts ...NativeScript) error {
+	for _, script := range scripts {
+		if err := tb.addNativeScript(script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Mint mints (positive quantity) or burns (negative quantity) assets under the policy of the native script.
+// The policy script is added to the witness set and the minted value is included when balancing the transaction.
+func (tb *TxBuilder) Mint(policy NativeScript, assets ...MintAsset) error {
+	hash, err := policy.Hash()
+	if err != nil {
+		return err
+	}
+	policyID, err := crypto.ScriptHashFromBytes(hash)
+	if err != nil {
+		return err
+	}
+
+	if tb.tx.Body.Mint == nil {
+		tb.tx.Body.Mint = Mint{}
+	}
+	for _, asset := range assets {
+		tb.tx.Body.Mint.Add(policyID, asset.Name, asset.Quantity)
+	}
+
+	return tb.addNativeScript(policy)
+}
+
+// addNativeScript adds the script to the witness set unless a script with the same hash is already present.
+func (tb *TxBuilder) addNativeScript(script NativeScript) error {
+	hash, err := script.Hash()
+	if err != nil {
+		return err
+	}
+	for _, witnessScript := range tb.tx.WitnessSet.Scripts {
+		witnessHash, err := witnessScript.Hash()
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(hash, witnessHash) {
+			return nil
+		}
+	}
+	tb.tx.WitnessSet.Scripts = append(tb.tx.WitnessSet.Scripts, script)
+
+	return nil
+}
+
+// AddPlutusScripts adds Plutus scripts to the witness set.
+func (tb *TxBuilder) AddPlutusScripts(scripts ...PlutusScript) {
+	for _, script := range scripts {
+		tb.tx.WitnessSet.AddPlutusScript(script)
+	}
+}
+
+// AddPlutusData adds datums to the witness set, e.g. the datums of inputs locked with a datum hash.
+func (tb *TxBuilder) AddPlutusData(datums ...PlutusData) {
+	for _, datum := range datums {
+		found := false
+		for _, d := range tb.tx.WitnessSet.PlutusData {
+			if bytes.Equal(d.Bytes(), datum.Bytes()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tb.tx.WitnessSet.PlutusData = append(tb.tx.WitnessSet.PlutusData, datum)
+		}
+	}
+}
+
+// AddRedeemers adds redeemers for minting, certificates, withdrawals or governance to the witness set.
+// Redeemers of spent inputs are added with SpendFromPlutusScript.
+func (tb *TxBuilder) AddRedeemers(redeemers ...Redeemer) {
+	tb.redeemers = append(tb.redeemers, redeemers...)
+}
+
+// SpendFromPlutusScript adds an input locked by the Plutus script, to be spent with the redeemer.
+// The datum must be given for inputs locked with a datum hash and is nil for inputs with an inline datum.
+// The redeemer index is computed from the position of the input in the sorted inputs when the transaction is built.
+func (tb *TxBuilder) SpendFromPlutusScript(input *TxInput, script PlutusScript, datum *PlutusData, redeemer PlutusData, exUnits ExUnits) {
+	tb.AddInputs(input)
+	tb.AddPlutusScripts(script)
+	if datum != nil {
+		tb.AddPlutusData(*datum)
+	}
+	tb.spends = append(tb.spends, scriptSpend{input: input, redeemer: redeemer, exUnits: exUnits})
+}
+
+// setScriptData sets the redeemers of the witness set and the script data hash of the body.
+func (tb *TxBuilder) setScriptData() error {
+	redeemers, hash, ok, err := tb.scriptData()
+	if err != nil || !ok {
+		return err
+	}
+	tb.tx.WitnessSet.Redeemers = redeemers
+	tb.tx.Body.ScriptDataHash = hash
+
+	return nil
+}
+
+// scriptData returns the redeemers of the witness set and the script data hash of the body,
+// or false if the transaction has no redeemers or datums.
+func (tb TxBuilder) scriptData() (redeemers Redeemers, hash []byte, ok bool, err error) {
+	if len(tb.spends) == 0 && len(tb.redeemers) == 0 && len(tb.tx.WitnessSet.PlutusData) == 0 {
+		return nil, nil, false, nil
+	}
+
+	inputs := make([]*TxInput, len(tb.tx.Body.Inputs))
+	copy(inputs, tb.tx.Body.Inputs)
+	sort.Slice(inputs, func(i, j int) bool {
+		if c := bytes.Compare(inputs[i].TxHash, inputs[j].TxHash); c != 0 {
+			return c < 0
+		}
+		return inputs[i].Index < inputs[j].Index
+	})
+
+	redeemers = Redeemers{}
+	for _, spend := range tb.spends {
+		index := -1
+		for i, input := range inputs {
+			if bytes.Equal(input.TxHash, spend.input.TxHash) && input.Index == spend.input.Index {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, nil, false, fmt.Errorf("script input %x#%d is not an input of the transaction", spend.input.TxHash, spend.input.Index)
+		}
+		redeemers = append(redeemers, NewRedeemer(RedeemerTagSpend, uint32(index), spend.redeemer, spend.exUnits))
+	}
+	redeemers = append(redeemers, tb.redeemers...)
+
+	languages := []PlutusVersion{}
+	for _, script := range tb.tx.WitnessSet.PlutusScripts() {
+		languages = append(languages, script.Version)
+	}
+	for _, input := range tb.tx.referenceScriptInputs() {
+		if script := input.ScriptRef.PlutusScript; script != nil {
+			languages = append(languages, script.Version)
+		}
+	}
+
+	hash, err = ScriptDataHash(redeemers, tb.tx.WitnessSet.PlutusData, tb.protocol.CostModels, languages...)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return redeemers, hash, true, nil
+}
+
+// SetCollateral makes the builder select the collateral of the transaction from the utxos.
+// Only pure ADA outputs locked by a key are used, largest first, up to the maxCollateralInputs protocol parameter.
+// The collateral exceeding collateralPercentage of the fee is returned to returnAddr, unless it is less than
+// the minimum lovelace of an output.
+func (tb *TxBuilder) SetCollateral(utxos []*TxInput, returnAddr address.Address) {
+	tb.collateral = utxos
+	tb.collateralReturn = returnAddr
+}
+
+// setCollateral selects the collateral for the current fee and sets the collateral fields of the body.
+func (tb *TxBuilder) setCollateral() error {
+	if tb.collateral == nil {
+		return nil
+	}
+
+	candidates := []*TxInput{}
+	for _, utxo := range tb.collateral {
+		if utxo.Address != nil && isKeyLocked(utxo.Address) && utxo.MultiAsset.IsZero() {
+			candidates = append(candidates, utxo)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Amount > candidates[j].Amount
+	})
+
+	// required = ceil(fee * collateralPercentage / 100)
+	required := (uint(tb.tx.Body.Fee)*tb.protocol.CollateralPercentage + 99) / 100
+
+	selected := []*TxInput{}
+	total := uint(0)
+	for _, utxo := range candidates {
+		if total >= required && len(selected) > 0 {
+			break
+		}
+		if tb.protocol.MaxCollateralInputs > 0 && uint(len(selected)) == tb.protocol.MaxCollateralInputs {
+			break
+		}
+		selected = append(selected, utxo)
+		total += utxo.Amount
+	}
+	if len(selected) == 0 || total < required {
+		return fmt.Errorf("%w: %d lovelace required", ErrNoCollateral, required)
+	}
+
+	tb.tx.Body.Collateral = selected
+	tb.tx.Body.CollateralReturn = nil
+	tb.tx.Body.TotalCollateral = uint64(total)
+	// collateral too small to be returned in a valid output is forfeited
+	if total > required {
+		collateralReturn := NewTxOutput(tb.collateralReturn, total-required)
+		minAmount, err := minOutputAmount(collateralReturn, tb.protocol)
+		if err != nil {
+			return err
+		}
+		if collateralReturn.Amount >= minAmount {
+			tb.tx.Body.CollateralReturn = collateralReturn
+			tb.tx.Body.TotalCollateral = uint64(required)
+		}
+	}
+
+	return nil
+}
+
+// isKeyLocked reports whether outputs at the address are spent with a key witness.
+func isKeyLocked(addr address.Address) bool {
+	if _, ok := addr.(*address.ByronAddress); ok {
+		return true
+	}
+	payment := paymentCredential(addr)
+	return payment != nil && payment.Kind == address.KeyStakeCredentialType
+}
+
+// maxFeeIterations bounds the passes made until the fee, which is part of the transaction, converges.
+const maxFeeIterations = 10
+
 // MinFee calculates the minimum fee for the provided transaction.
-func (tb TxBuilder) MinFee() (fee uint) {
+// Since the transaction is signed after the fee is set, the witness set is estimated from the keys
+// and Byron addresses expected to witness the transaction.
+// The transaction itself is not modified.
+func (tb TxBuilder) MinFee() (fee uint, err error) {
+	body := *tb.tx.Body
+	body.raw = rawBytes{}
+	witnessSet := *tb.tx.WitnessSet
+	witnessSet.raw = rawBytes{}
+	witnessSet.Witnesses, witnessSet.BootstrapWitnesses, err = tb.estimatedWitnesses()
+	if err != nil {
+		return 0, err
+	}
+
+	redeemers, hash, ok, err := tb.scriptData()
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		witnessSet.Redeemers = redeemers
+		body.ScriptDataHash = hash
+	}
 	feeTx := Tx{
-		Body: &TxBody{
-			Inputs:  tb.tx.Body.Inputs,
-			Outputs: tb.tx.Body.Outputs,
-			Fee:     tb.tx.Body.Fee,
-			TTL:     tb.tx.Body.TTL,
-		},
-		WitnessSet:    tb.tx.WitnessSet,
+		Body:          &body,
+		WitnessSet:    &witnessSet,
 		Valid:         true,
 		AuxiliaryData: tb.tx.AuxiliaryData,
 	}
-	feeTx.CalculateAuxiliaryDataHash()
-	if len(feeTx.WitnessSet.Witnesses) == 0 {
-		vWitness := NewVKeyWitness(
-			make([]byte, 32),
-			make([]byte, 64),
-		)
-		feeTx.WitnessSet.Witnesses = append(feeTx.WitnessSet.Witnesses, vWitness)
+	if err := feeTx.CalculateAuxiliaryDataHash(); err != nil {
+		return 0, err
 	}
 
-	// Not realy sure about this part of the function
-	// commented for further discusion
+	// The fee may increase the number of bytes of the transaction, so recalculate until it stops changing
+	for i := 0; i < maxFeeIterations; i++ {
+		newFee, err := feeTx.Fee(tb.feeCalculator())
+		if err != nil {
+			return 0, err
+		}
+		if newFee == fee {
+			break
+		}
+		fee = newFee
+		feeTx.Body.Fee = uint64(fee)
+	}
 
-	// totalI, totalO := tb.GetTotalInputOutputs()
+	return fee, nil
+}
 
-	// if totalI != (totalO) {
-	// 	inner_addr, _ := address.NewAddress("addr_test1qqe6zztejhz5hq0xghlf72resflc4t2gmu9xjlf73x8dpf88d78zlt4rng3ccw8g5vvnkyrvt96mug06l5eskxh8rcjq2wyd63")
+// witnessPlan returns the key hashes expected to sign the transaction with vkey witnesses and the Byron
+// addresses expected to be witnessed with bootstrap witnesses.
+// Keys of native scripts are all expected to sign, so the plan may overestimate the witnesses.
+func (tb TxBuilder) witnessPlan() (keyHashes []Hash28, byronAddrs []*address.ByronAddress, err error) {
+	seen := map[string]bool{}
+	add := func(keyHash []byte) {
+		if !seen[string(keyHash)] {
+			seen[string(keyHash)] = true
+			keyHashes = append(keyHashes, keyHash)
+		}
+	}
 
-	// 	feeTx.Body.Outputs = append(feeTx.Body.Outputs, NewTxOutput(inner_addr, (totalI-totalO-200000)))
+	creds, err := tb.requiredCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cred := range creds {
+		if cred.Kind == address.KeyStakeCredentialType {
+			add(cred.Payload)
+		}
+	}
+	for _, script := range tb.tx.WitnessSet.Scripts {
+		for _, keyHash := range script.keyHashes() {
+			add(keyHash)
+		}
+	}
 
-	// }
+	byronKeys := map[int]bool{}
+	byronAddrs = tb.byronInputs()
+	for _, addr := range byronAddrs {
+		if i, ok := tb.byronSigner(addr); ok {
+			byronKeys[i] = true
+		}
+	}
+	// Build signs with every supplied key
+	for i, prv := range tb.xprvs {
+		if !byronKeys[i] {
+			keyHash := prv.Public().PublicKey().Hash()
+			add(keyHash[:])
+		}
+	}
 
-	lfee := fees.NewLinearFee(tb.protocol.TxFeePerByte, tb.protocol.TxFeeFixed)
-	// The fee may have increased enough to increase the number of bytes, so do one more pass
-	fee, _ = feeTx.Fee(lfee)
-	feeTx.Body.Fee = uint64(fee)
-	fee, _ = feeTx.Fee(lfee)
+	return keyHashes, byronAddrs, nil
+}
 
-	return
+// estimatedWitnesses returns witnesses of the size of the witnesses of the witness plan.
+func (tb TxBuilder) estimatedWitnesses() ([]VKeyWitness, []BootstrapWitness, error) {
+	keyHashes, byronAddrs, err := tb.witnessPlan()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vkeyWitnesses := []VKeyWitness{}
+	for range keyHashes {
+		vkeyWitnesses = append(vkeyWitnesses, NewVKeyWitness(make([]byte, 32), make([]byte, 64)))
+	}
+
+	bootstrapWitnesses := []BootstrapWitness{}
+	for _, addr := range byronAddrs {
+		witness, err := NewBootstrapWitness(make([]byte, 32), make([]byte, 64), make([]byte, 32), addr)
+		if err != nil {
+			continue
+		}
+		bootstrapWitnesses = append(bootstrapWitnesses, witness)
+	}
+
+	// inputs without a known address are expected to be signed by one key
+	if len(vkeyWitnesses) == 0 && len(bootstrapWitnesses) == 0 {
+		vkeyWitnesses = append(vkeyWitnesses, NewVKeyWitness(make([]byte, 32), make([]byte, 64)))
+	}
+
+	return vkeyWitnesses, bootstrapWitnesses, nil
+}
+
+// feeCalculator returns the fee equation of the protocol parameters.
+func (tb TxBuilder) feeCalculator() fees.Calculator {
+	return fees.NewScriptFee(
+		fees.NewLinearFee(tb.protocol.TxFeePerByte, tb.protocol.TxFeeFixed),
+		tb.protocol.ExecutionUnitPrices.PriceMemory,
+		tb.protocol.ExecutionUnitPrices.PriceSteps,
+		tb.protocol.MinFeeRefScriptCostPerByte,
+	)
+}
+
+// AddReferenceInputs adds inputs that are read by the transaction but not spent, e.g. outputs
+// carrying reference scripts or oracle datums. They are not included when balancing the transaction.
+func (tb *TxBuilder) AddReferenceInputs(inputs ...*TxInput) {
+	tb.tx.Body.ReferenceInputs = append(tb.tx.Body.ReferenceInputs, inputs...)
 }
 
 // AddInputs adds inputs to the transaction body
@@ -138,6 +938,30 @@ func (tb *TxBuilder) AddOutputs(outputs ...*TxOutput) {
 	tb.tx.AddOutputs(outputs...)
 }
 
+// LockAtScript adds an output sending value to the script address addr, locked with the inline datum.
+func (tb *TxBuilder) LockAtScript(addr address.Address, value *Value, datum PlutusData) error {
+	payment := paymentCredential(addr)
+	if payment == nil || payment.Kind != address.ScriptStakeCredentialType {
+		return fmt.Errorf("%w: %s", ErrNotScriptAddress, addr.String())
+	}
+
+	tb.AddOutputs(NewTxOutputWithInlineDatum(addr, value, datum))
+	return nil
+}
+
+// paymentCredential returns the payment credential of the address, or nil if the address has none.
+func paymentCredential(addr address.Address) *address.StakeCredential {
+	switch a := addr.(type) {
+	case *address.BaseAddress:
+		return &a.Payment
+	case *address.EnterpriseAddress:
+		return &a.Payment
+	case *address.PointerAddress:
+		return &a.Payment
+	}
+	return nil
+}
+
 // NewTxBuilder returns pointer to a new TxBuilder.
 func NewTxBuilder(pr protocol.Protocol, xprvs []bip32.XPrv) *TxBuilder {
 	return &TxBuilder{
@@ -93,6 +93,16 @@ func generateBaseAddress(net *network.NetworkInfo) (addr *address.BaseAddress, u
 	return
 }
 
+func loadTestProtocol(t *testing.T) protocol.Protocol {
+	t.Helper()
+	basepath := filepath.Dir(packagepath)
+	pr, err := protocol.LoadProtocol(filepath.Join(basepath, "testdata", "protocol", "protocol.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *pr
+}
+
 func getTxDetails(fp string) (txD txDetails) {
 	data, err := readJson(fp)
 	if err != nil {
@@ -241,7 +251,7 @@ func TestMultisigTx(t *testing.T) {
 	}
 
 	// Set TTL for 5 min into the future
-	builder.SetTTL(uint32(tip.Slot) + uint32(300))
+	builder.SetTTL(uint64(tip.Slot) + uint64(300))
 
 	// Create script of multisig address
 	firstSignerKeyHash, _ := hex.DecodeString("d8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1")
@@ -309,7 +319,7 @@ func TestMultisigTx(t *testing.T) {
 	))
 
 	// Calculate fee
-	builder.Tx().SetFee(builder.MinFee())
+	builder.Tx().SetFee(minFee(t, builder))
 
 	// Update multisig change to = input - fee - output
 	change := totalI - totalO - uint(builder.Tx().Body.Fee)
@@ -456,7 +466,7 @@ func TestSimpleTx(t *testing.T) {
 	}
 
 	// Set TTL for 5 min into the future
-	builder.SetTTL(uint32(tip.Slot) + uint32(300))
+	builder.SetTTL(uint64(tip.Slot) + uint64(300))
 
 	// Set metadata
 	builder.Tx().AuxiliaryData = tx.NewAuxiliaryData()
@@ -543,7 +553,7 @@ func TestTxBuilderRaw(t *testing.T) {
 			if err != nil {
 				log.Fatal(err)
 			}
-			builder.SetTTL(uint32(txD.SlotNo))
+			builder.SetTTL(uint64(txD.SlotNo))
 			builder.AddChangeIfNeeded(changeAddr)
 
 			builder.Sign(
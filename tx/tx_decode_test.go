@@ -0,0 +1,150 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func goldenTxHex(t *testing.T, name string) string {
+	t.Helper()
+	basepath := filepath.Dir(packagepath)
+	data, err := ioutil.ReadFile(filepath.Join(basepath, "testdata", "transaction", "tx_builder", "golden", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func TestNewTxFromHex(t *testing.T) {
+	for _, golden := range []string{"raw_tx_base.golden", "raw_tx_ent.golden"} {
+		t.Run(golden, func(t *testing.T) {
+			txHex := goldenTxHex(t, golden)
+
+			decoded, err := tx.NewTxFromHex(txHex)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Len(t, decoded.Body.Inputs, 1)
+			assert.Equal(t, uint16(0), decoded.Body.Inputs[0].Index)
+			assert.Len(t, decoded.Body.Outputs, 2)
+			assert.Equal(t, uint(5000000), decoded.Body.Outputs[0].Amount)
+			assert.Equal(t, uint64(55267575), decoded.Body.TTL)
+			assert.Len(t, decoded.WitnessSet.Witnesses, 1)
+			assert.True(t, decoded.Valid)
+			assert.Nil(t, decoded.AuxiliaryData)
+
+			reencoded, err := decoded.Hex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, txHex, reencoded)
+		})
+	}
+}
+
+func TestNewTxFromHexTaggedSetsAndShelleyMetadata(t *testing.T) {
+	// Inputs are a tag 258 set and the auxiliary data uses the Shelley format: {1: {"string": "value"}}
+	txHex := "84a300d9010281825820fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c38000" +
+		"018182581d61a597e3dc6a2f0b110c31c42abf0965b9705dd3f291c6091a277b36971a004c4b40021a000290cd" +
+		"a0f5a101a166737472696e676576616c7565"
+
+	decoded, err := tx.NewTxFromHex(txHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, decoded.Body.Inputs, 1)
+	assert.IsType(t, &address.EnterpriseAddress{}, decoded.Body.Outputs[0].Address)
+	assert.Equal(t, uint64(168141), decoded.Body.Fee)
+	assert.Equal(t, "value", decoded.AuxiliaryData.Metadata[1]["string"])
+}
+
+func TestNewTxFromBytesInvalid(t *testing.T) {
+	_, err := tx.NewTxFromBytes([]byte{0x80})
+	assert.Error(t, err)
+}
+
+func TestDecodedTxKeepsOriginalBytes(t *testing.T) {
+	// Shelley-MA auxiliary data: [{1: {"string": "value"}}, []]
+	auxHex := "82a101a166737472696e676576616c756580"
+	aux, _ := hex.DecodeString(auxHex)
+	auxHash := blake2b.Sum256(aux)
+
+	// Body with non-canonical key order {7, 2, 0, 1} and an indefinite-length input array
+	bodyHex := "a4075820" + hex.EncodeToString(auxHash[:]) + "021a000290cd" +
+		"009f825820fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c38000ff" +
+		"018182581d61a597e3dc6a2f0b110c31c42abf0965b9705dd3f291c6091a277b36971a004c4b40"
+	body, _ := hex.DecodeString(bodyHex)
+
+	txHex := "84" + bodyHex + "a0f5" + auxHex
+
+	decoded, err := tx.NewTxFromHex(txHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := decoded.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, blake2b.Sum256(body), hash)
+	assert.Equal(t, auxHash[:], decoded.Body.AuxiliaryDataHash)
+
+	reencoded, err := decoded.Hex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, txHex, reencoded)
+
+	// A modified body is encoded again
+	decoded.SetFee(200000)
+	hash, err = decoded.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEqual(t, blake2b.Sum256(body), hash)
+}
+
+func TestValidityInterval(t *testing.T) {
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+	builder.AddOutputs(tx.NewTxOutput(addr, 1000000))
+
+	body, err := builder.Tx().Body.Hex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only keys 0, 1 and 2 are present when the validity interval is not set
+	assert.Equal(t, "a3", body[:2])
+
+	// Slots past 2^32
+	builder.SetValidityIntervalStart(1 << 32)
+	builder.SetTTL(1<<32 + 300)
+
+	data, err := builder.Tx().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(1<<32), decoded.Body.ValidityIntervalStart)
+	assert.Equal(t, uint64(1<<32+300), decoded.Body.TTL)
+}
@@ -13,12 +13,22 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/milos-ethernal/go-cardano-serialization/address"
 	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/fees"
 	"github.com/milos-ethernal/go-cardano-serialization/network"
 	"github.com/milos-ethernal/go-cardano-serialization/node"
 	"github.com/milos-ethernal/go-cardano-serialization/tx"
 	"github.com/stretchr/testify/assert"
 )
 
+func minFee(t *testing.T, builder *tx.TxBuilder) uint {
+	t.Helper()
+	fee, err := builder.MinFee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fee
+}
+
 func TestFeeForMultisigTx(t *testing.T) {
 	// Load env variables
 	err := godotenv.Load()
@@ -88,7 +98,7 @@ func TestFeeForMultisigTx(t *testing.T) {
 	}
 
 	// Set TTL for 5 min into the future
-	builder.SetTTL(uint32(tip.Slot) + uint32(300))
+	builder.SetTTL(uint64(tip.Slot) + uint64(300))
 
 	// Create script of multisig address
 	firstSignerKeyHash, _ := hex.DecodeString("d8f3f9ee291c253b7c12f4103f91f73026ec32690ad9bc99cc95f8f1")
@@ -157,7 +167,7 @@ func TestFeeForMultisigTx(t *testing.T) {
 
 	// Calculate fee
 	// But don't set it so we can check node error output with our calculation
-	calculatedFee := builder.MinFee()
+	calculatedFee := minFee(t, builder)
 
 	// Update multisig change to = input - fee - output
 	change := totalI - totalO - uint(builder.Tx().Body.Fee)
@@ -299,7 +309,7 @@ func TestFeeForSimpleTx(t *testing.T) {
 	}
 
 	// Set TTL for 5 min into the future
-	builder.SetTTL(uint32(tip.Slot) + uint32(300))
+	builder.SetTTL(uint64(tip.Slot) + uint64(300))
 
 	// Route back the change to the source address
 	// This is equivalent to adding an output with the source address and change amount
@@ -340,3 +350,80 @@ func TestFeeForSimpleTx(t *testing.T) {
 	assert.Equal(t, 400, statusCode)
 	assert.Equal(t, calculatedFee, nodeFee)
 }
+
+func TestMinFeeWitnessPlan(t *testing.T) {
+	pr := loadTestProtocol(t)
+	lfee := fees.NewLinearFee(pr.TxFeePerByte, pr.TxFeeFixed)
+	accountKey := createRootKey().Derive(harden(1852)).Derive(harden(1815)).Derive(harden(0))
+	keys := []bip32.XPrv{accountKey.Derive(0).Derive(0), accountKey.Derive(0).Derive(1), accountKey.Derive(0).Derive(2)}
+
+	keyAddr := func(key bip32.XPrv) address.Address {
+		keyHash := key.Public().PublicKey().Hash()
+		return address.NewEnterpriseAddress(network.TestNet(), address.NewKeyStakeCredential(keyHash[:]))
+	}
+
+	scripts := []tx.NativeScript{}
+	for _, key := range keys {
+		keyHash := key.Public().PublicKey().Hash()
+		script, err := tx.NewScriptPubKey(keyHash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		scripts = append(scripts, script)
+	}
+	multisig := tx.NativeScript{Type: tx.ScriptAll, Scripts: scripts}
+
+	for _, sc := range []struct {
+		description string
+		signers     []bip32.XPrv
+		build       func(builder *tx.TxBuilder)
+	}{
+		{
+			description: "inputs of two keys",
+			signers:     keys[:2],
+			build: func(builder *tx.TxBuilder) {
+				builder.AddInputs(
+					tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(keyAddr(keys[0]), 5000000)),
+					tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, tx.NewTxOutput(keyAddr(keys[1]), 5000000)),
+				)
+			},
+		},
+		{
+			description: "multisig native script",
+			signers:     keys,
+			build: func(builder *tx.TxBuilder) {
+				builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+				if err := builder.AddNativeScripts(multisig); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	} {
+		t.Run(sc.description, func(t *testing.T) {
+			builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+			sc.build(builder)
+			builder.AddOutputs(tx.NewTxOutput(keyAddr(keys[2]), 2000000))
+			if err := builder.AddChangeIfNeeded(keyAddr(keys[0])); err != nil {
+				t.Fatal(err)
+			}
+			// fee estimation does not leave witnesses in the transaction
+			assert.Empty(t, builder.Tx().WitnessSet.Witnesses)
+
+			// the keys are supplied after balancing, as with offline signing
+			for _, key := range sc.signers {
+				builder.Sign(key)
+			}
+			txFinal, err := builder.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Len(t, txFinal.WitnessSet.Witnesses, len(sc.signers))
+
+			fee, err := txFinal.Fee(lfee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fee, uint(txFinal.Body.Fee))
+		})
+	}
+}
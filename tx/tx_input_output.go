@@ -2,52 +2,251 @@ package tx
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 
-	"github.com/fxamacker/cbor/v2"
 	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/internal/bech32/cbor"
 )
 
+// TxInput references an unspent transaction output.
+// The Address, Value and ScriptRef of the output are used for balancing, collateral selection
+// and fee calculation and are not serialized.
 type TxInput struct {
-	cbor.Marshaler
+	TxHash    []byte
+	Index     uint16
+	Address   address.Address
+	ScriptRef *ScriptRef
+	Value
 
-	TxHash []byte
-	Index  uint16
-	Amount uint
+	// output is the spent output, if the input was created from it.
+	output *TxOutput
 }
 
 // NewTxInput creates and returns a *TxInput from Transaction Hash(Hex Encoded), Transaction Index and Amount.
 func NewTxInput(txHash string, txIx uint16, amount uint) *TxInput {
+	return NewTxInputWithValue(txHash, txIx, NewValue(amount))
+}
+
+// NewTxInputFromOutput creates and returns a *TxInput spending the output, e.g. a UTxO returned by a node.
+func NewTxInputFromOutput(txHash string, txIx uint16, output *TxOutput) *TxInput {
+	input := NewTxInputWithValue(txHash, txIx, &output.Value)
+	input.Address = output.Address
+	input.ScriptRef = output.ScriptRef
+	input.output = output
+
+	return input
+}
+
+// NewTxInputWithValue creates and returns a *TxInput from Transaction Hash(Hex Encoded), Transaction Index and Value.
+func NewTxInputWithValue(txHash string, txIx uint16, value *Value) *TxInput {
 	hash, _ := hex.DecodeString(txHash)
 
 	return &TxInput{
 		TxHash: hash,
 		Index:  txIx,
-		Amount: amount,
+		Value:  *value.Clone(),
 	}
 }
 
+type arrayInput struct {
+	_      struct{} `cbor:",toarray"`
+	TxHash []byte
+	Index  uint16
+}
+
 func (txI *TxInput) MarshalCBOR() ([]byte, error) {
-	type arrayInput struct {
-		_      struct{} `cbor:",toarray"`
-		TxHash []byte
-		Index  uint16
-	}
 	input := arrayInput{
 		TxHash: txI.TxHash,
 		Index:  txI.Index,
 	}
-	return cbor.Marshal(input)
+	return cborEnc.Marshal(input)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+// The Value of a decoded input is unknown and left as zero.
+func (txI *TxInput) UnmarshalCBOR(data []byte) error {
+	var input arrayInput
+	if err := cborDec.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	txI.TxHash = input.TxHash
+	txI.Index = input.Index
+
+	return nil
 }
 
+// TxOutput is a transaction output.
+// Outputs with an inline datum or a reference script are encoded in the post-Alonzo (map) format,
+// other outputs in the legacy (array) format.
 type TxOutput struct {
-	_       struct{} `cbor:",toarray"`
 	Address address.Address
-	Amount  uint
+	Value
+	// DatumHash is the hash of the datum locking the output.
+	DatumHash []byte
+	// Datum is the inline datum locking the output.
+	Datum *PlutusData
+	// ScriptRef is a script that transactions referencing the output can use.
+	ScriptRef *ScriptRef
+
+	postAlonzo bool
 }
 
+// NewTxOutput creates and returns a *TxOutput sending amount lovelace to addr.
 func NewTxOutput(addr address.Address, amount uint) *TxOutput {
+	return NewTxOutputWithValue(addr, NewValue(amount))
+}
+
+// NewTxOutputWithValue creates and returns a *TxOutput sending value (lovelace and tokens) to addr.
+func NewTxOutputWithValue(addr address.Address, value *Value) *TxOutput {
 	return &TxOutput{
 		Address: addr,
-		Amount:  amount,
+		Value:   *value.Clone(),
 	}
 }
+
+// NewTxOutputWithDatumHash creates and returns a *TxOutput sending value to addr, locked with the datum hash.
+func NewTxOutputWithDatumHash(addr address.Address, value *Value, datumHash []byte) *TxOutput {
+	output := NewTxOutputWithValue(addr, value)
+	output.DatumHash = datumHash
+
+	return output
+}
+
+// NewTxOutputWithInlineDatum creates and returns a *TxOutput sending value to addr, locked with the inline datum.
+func NewTxOutputWithInlineDatum(addr address.Address, value *Value, datum PlutusData) *TxOutput {
+	output := NewTxOutputWithValue(addr, value)
+	output.Datum = &datum
+
+	return output
+}
+
+func (txO *TxOutput) isPostAlonzo() bool {
+	return txO.postAlonzo || txO.Datum != nil || txO.ScriptRef != nil
+}
+
+type postAlonzoOutput struct {
+	Address     []byte          `cbor:"0,keyasint"`
+	Value       Value           `cbor:"1,keyasint"`
+	DatumOption cbor.RawMessage `cbor:"2,keyasint,omitempty"`
+	ScriptRef   *ScriptRef      `cbor:"3,keyasint,omitempty"`
+}
+
+type datumOption struct {
+	_     struct{} `cbor:",toarray"`
+	Type  uint64
+	Datum cbor.RawMessage
+}
+
+const (
+	datumOptionHash uint64 = iota
+	datumOptionInline
+)
+
+// MarshalCBOR implements cbor.Marshaler.
+func (txO *TxOutput) MarshalCBOR() ([]byte, error) {
+	if !txO.isPostAlonzo() {
+		if txO.DatumHash != nil {
+			return cborEnc.Marshal([]interface{}{txO.Address.Bytes(), &txO.Value, txO.DatumHash})
+		}
+		return cborEnc.Marshal([]interface{}{txO.Address.Bytes(), &txO.Value})
+	}
+
+	output := postAlonzoOutput{
+		Address:   txO.Address.Bytes(),
+		Value:     txO.Value,
+		ScriptRef: txO.ScriptRef,
+	}
+
+	var option []interface{}
+	switch {
+	case txO.Datum != nil:
+		datum, err := wrapCBOR(txO.Datum.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		option = []interface{}{datumOptionInline, cbor.RawMessage(datum)}
+	case txO.DatumHash != nil:
+		option = []interface{}{datumOptionHash, txO.DatumHash}
+	}
+	if option != nil {
+		data, err := cborEnc.Marshal(option)
+		if err != nil {
+			return nil, err
+		}
+		output.DatumOption = data
+	}
+
+	return cborEnc.Marshal(output)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+// Both the legacy [address, value, ? datum_hash] and the post-Alonzo map format are accepted.
+func (txO *TxOutput) UnmarshalCBOR(data []byte) error {
+	var output postAlonzoOutput
+	*txO = TxOutput{}
+
+	if len(data) > 0 && data[0]>>5 == cborMajorTypeMap {
+		if err := cborDec.Unmarshal(data, &output); err != nil {
+			return err
+		}
+		txO.postAlonzo = true
+		txO.ScriptRef = output.ScriptRef
+
+		if output.DatumOption != nil {
+			var option datumOption
+			if err := cborDec.Unmarshal(output.DatumOption, &option); err != nil {
+				return err
+			}
+			switch option.Type {
+			case datumOptionHash:
+				if err := cborDec.Unmarshal(option.Datum, &txO.DatumHash); err != nil {
+					return err
+				}
+			case datumOptionInline:
+				datum, err := unwrapCBOR(option.Datum)
+				if err != nil {
+					return err
+				}
+				plutusData, err := NewPlutusDataFromCBOR(datum)
+				if err != nil {
+					return err
+				}
+				txO.Datum = &plutusData
+			default:
+				return fmt.Errorf("cbor: unknown datum option %d", option.Type)
+			}
+		}
+	} else {
+		var fields []cbor.RawMessage
+		if err := cborDec.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		if len(fields) != 2 && len(fields) != 3 {
+			return fmt.Errorf("cbor: transaction output has %d fields", len(fields))
+		}
+		if err := cborDec.Unmarshal(fields[0], &output.Address); err != nil {
+			return err
+		}
+		if err := cborDec.Unmarshal(fields[1], &output.Value); err != nil {
+			return err
+		}
+		if len(fields) == 3 {
+			if err := cborDec.Unmarshal(fields[2], &txO.DatumHash); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(output.Address) == 0 {
+		return errors.New("cbor: empty address in transaction output")
+	}
+	addr, err := address.NewAddressFromBytes(output.Address)
+	if err != nil {
+		return err
+	}
+	txO.Address = addr
+	txO.Value = output.Value
+
+	return nil
+}
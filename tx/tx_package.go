@@ -0,0 +1,531 @@
+package tx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/protocol"
+)
+
+var ErrUnresolvedInput = errors.New("input missing from the package UTxOs")
+var ErrNoNetwork = errors.New("unsigned tx package has no network")
+
+// UnsignedTxPackageType is the type of the JSON encoding of an UnsignedTxPackage.
+const UnsignedTxPackageType = "Unsigned Tx Package"
+
+const unsignedTxPackageVersion = 1
+
+// UnsignedTxPackage bundles an unsigned transaction with the outputs it spends and references, the network
+// and the protocol parameters it was built with, so that an offline signer can show the amounts, fee and
+// change of the transaction before signing it.
+type UnsignedTxPackage struct {
+	Tx *Tx
+	// UTxOs are the outputs spent and referenced by the transaction.
+	UTxOs    []*TxInput
+	Network  *network.NetworkInfo
+	Protocol protocol.Protocol
+}
+
+// NewUnsignedTxPackage returns a pointer to a new UnsignedTxPackage of the transaction.
+func NewUnsignedTxPackage(tx *Tx, utxos []*TxInput, net *network.NetworkInfo, pr protocol.Protocol) *UnsignedTxPackage {
+	return &UnsignedTxPackage{
+		Tx:       tx,
+		UTxOs:    utxos,
+		Network:  net,
+		Protocol: pr,
+	}
+}
+
+// TxSummary is a human readable summary of a transaction.
+type TxSummary struct {
+	Inputs  []OutputSummary `json:"inputs"`
+	Outputs []OutputSummary `json:"outputs"`
+	Fee     uint64          `json:"fee"`
+	// Change is the lovelace sent back to the addresses of the inputs.
+	Change          uint     `json:"change"`
+	Withdrawals     uint     `json:"withdrawals,omitempty"`
+	Deposits        uint     `json:"deposits,omitempty"`
+	Refunds         uint     `json:"refunds,omitempty"`
+	Donation        uint64   `json:"donation,omitempty"`
+	ValidityStart   uint64   `json:"validityStart,omitempty"`
+	TTL             uint64   `json:"ttl,omitempty"`
+	RequiredSigners []string `json:"requiredSigners,omitempty"`
+	// Mint maps policyId.assetName (hex encoded) to the quantities minted (positive) or burned (negative).
+	Mint map[string]int64 `json:"mint,omitempty"`
+	// Collateral are the collateral inputs, lost if a Plutus script of the transaction fails.
+	Collateral       []OutputSummary `json:"collateral,omitempty"`
+	CollateralReturn *OutputSummary  `json:"collateralReturn,omitempty"`
+	// TotalCollateral is the lovelace of the collateral inputs that is not returned by the collateral return.
+	TotalCollateral uint64               `json:"totalCollateral,omitempty"`
+	Certificates    []CertificateSummary `json:"certificates,omitempty"`
+	Votes           []VoteSummary        `json:"votes,omitempty"`
+}
+
+// OutputSummary is a human readable summary of an input or output of a transaction.
+type OutputSummary struct {
+	// TxIn is the hash#index of the output spent by an input.
+	TxIn     string `json:"txIn,omitempty"`
+	Address  string `json:"address"`
+	Lovelace uint   `json:"lovelace"`
+	// Assets maps policyId.assetName (hex encoded) to quantities.
+	Assets map[string]uint64 `json:"assets,omitempty"`
+	// Change reports whether the output is sent back to the address of an input.
+	Change bool `json:"change,omitempty"`
+}
+
+// CertificateSummary is a human readable summary of a certificate of a transaction.
+type CertificateSummary struct {
+	Type string `json:"type"`
+	// Credential is the stake credential, or the cold credential of committee certificates and the
+	// DRep credential of DRep certificates.
+	Credential    string `json:"credential,omitempty"`
+	Pool          string `json:"pool,omitempty"`
+	DRep          string `json:"drep,omitempty"`
+	HotCredential string `json:"hotCredential,omitempty"`
+	Deposit       uint   `json:"deposit,omitempty"`
+	Refund        uint   `json:"refund,omitempty"`
+}
+
+// VoteSummary is a human readable summary of a vote of a transaction.
+type VoteSummary struct {
+	Voter string `json:"voter"`
+	// GovActionID is the hash#index of the proposal of the governance action.
+	GovActionID string `json:"govActionId"`
+	Vote        string `json:"vote"`
+}
+
+var certificateNames = map[CertificateType]string{
+	CertStakeRegistration:               "stakeRegistration",
+	CertStakeDeregistration:             "stakeDeregistration",
+	CertStakeDelegation:                 "stakeDelegation",
+	CertPoolRegistration:                "poolRegistration",
+	CertPoolRetirement:                  "poolRetirement",
+	CertGenesisKeyDelegation:            "genesisKeyDelegation",
+	CertMoveInstantaneousRewards:        "moveInstantaneousRewards",
+	CertRegistration:                    "registration",
+	CertUnregistration:                  "unregistration",
+	CertVoteDelegation:                  "voteDelegation",
+	CertStakeVoteDelegation:             "stakeVoteDelegation",
+	CertStakeRegistrationDelegation:     "stakeRegistrationDelegation",
+	CertVoteRegistrationDelegation:      "voteRegistrationDelegation",
+	CertStakeVoteRegistrationDelegation: "stakeVoteRegistrationDelegation",
+	CertCommitteeHotAuth:                "committeeHotAuth",
+	CertCommitteeColdResign:             "committeeColdResign",
+	CertDRepRegistration:                "drepRegistration",
+	CertDRepUnregistration:              "drepUnregistration",
+	CertDRepUpdate:                      "drepUpdate",
+}
+
+var voterNames = map[VoterType]string{
+	VoterCommitteeHotKey:    "committeeHotKeyHash",
+	VoterCommitteeHotScript: "committeeHotScriptHash",
+	VoterDRepKey:            "drepKeyHash",
+	VoterDRepScript:         "drepScriptHash",
+	VoterStakePool:          "stakePool",
+}
+
+var voteNames = map[Vote]string{
+	VoteNo:      "no",
+	VoteYes:     "yes",
+	VoteAbstain: "abstain",
+}
+
+// credentialSummary returns the kind and hex encoded hash of the credential, or "" for nil.
+func credentialSummary(cred *address.StakeCredential) string {
+	if cred == nil {
+		return ""
+	}
+	if cred.Kind == address.ScriptStakeCredentialType {
+		return fmt.Sprintf("scriptHash %x", cred.Payload)
+	}
+	return fmt.Sprintf("keyHash %x", cred.Payload)
+}
+
+func newCertificateSummary(cert *Certificate, pr protocol.Protocol) CertificateSummary {
+	summary := CertificateSummary{
+		Type:          certificateNames[cert.Type],
+		Credential:    credentialSummary(cert.StakeCredential),
+		HotCredential: credentialSummary(cert.HotCredential),
+	}
+	if summary.Type == "" {
+		summary.Type = fmt.Sprintf("certificate %d", cert.Type)
+	}
+	if len(cert.PoolKeyHash) > 0 {
+		summary.Pool = hex.EncodeToString(cert.PoolKeyHash)
+	}
+	switch cert.Type {
+	case CertVoteDelegation, CertStakeVoteDelegation, CertVoteRegistrationDelegation, CertStakeVoteRegistrationDelegation:
+		switch cert.DRep.Type {
+		case DRepKeyHash:
+			summary.DRep = fmt.Sprintf("keyHash %x", []byte(cert.DRep.Hash))
+		case DRepScriptHash:
+			summary.DRep = fmt.Sprintf("scriptHash %x", []byte(cert.DRep.Hash))
+		case DRepAlwaysAbstain:
+			summary.DRep = "alwaysAbstain"
+		case DRepAlwaysNoConfidence:
+			summary.DRep = "alwaysNoConfidence"
+		}
+	}
+	summary.Deposit, summary.Refund = cert.deposits(pr)
+	return summary
+}
+
+func newOutputSummary(addr string, value *Value) OutputSummary {
+	summary := OutputSummary{Address: addr, Lovelace: value.Amount}
+	for policy, assets := range value.MultiAsset {
+		for name, quantity := range assets {
+			if summary.Assets == nil {
+				summary.Assets = map[string]uint64{}
+			}
+			summary.Assets[fmt.Sprintf("%x.%x", policy[:], []byte(name))] = quantity
+		}
+	}
+	return summary
+}
+
+// Summary returns the summary of the transaction, with the amounts of the inputs taken from the UTxOs.
+// ErrUnresolvedInput is returned if an input is missing from the UTxOs.
+func (p *UnsignedTxPackage) Summary() (TxSummary, error) {
+	resolved, missing := resolveTx(p.Tx, p.UTxOs)
+	if len(missing) > 0 {
+		return TxSummary{}, fmt.Errorf("%w: %x#%d", ErrUnresolvedInput, missing[0].TxHash, missing[0].Index)
+	}
+	body := resolved.Body
+
+	summary := TxSummary{
+		Inputs:          []OutputSummary{},
+		Outputs:         []OutputSummary{},
+		Fee:             body.Fee,
+		Withdrawals:     body.Withdrawals.Total(),
+		Donation:        body.Donation,
+		ValidityStart:   body.ValidityIntervalStart,
+		TTL:             body.TTL,
+		RequiredSigners: []string{},
+	}
+
+	inputSummaries := func(inputs []*TxInput) ([]OutputSummary, error) {
+		summaries := []OutputSummary{}
+		for _, input := range inputs {
+			if input.Address == nil {
+				return nil, fmt.Errorf("%w: %x#%d has no address", ErrUnresolvedInput, input.TxHash, input.Index)
+			}
+			inputSummary := newOutputSummary(input.Address.String(), &input.Value)
+			inputSummary.TxIn = fmt.Sprintf("%x#%d", input.TxHash, input.Index)
+			summaries = append(summaries, inputSummary)
+		}
+		return summaries, nil
+	}
+
+	inputs, err := inputSummaries(body.Inputs)
+	if err != nil {
+		return TxSummary{}, err
+	}
+	summary.Inputs = inputs
+	inputAddrs := [][]byte{}
+	for _, input := range body.Inputs {
+		inputAddrs = append(inputAddrs, input.Address.Bytes())
+	}
+
+	for _, output := range body.Outputs {
+		outputSummary := newOutputSummary(output.Address.String(), &output.Value)
+		for _, addr := range inputAddrs {
+			if bytes.Equal(addr, output.Address.Bytes()) {
+				outputSummary.Change = true
+				summary.Change += output.Amount
+				break
+			}
+		}
+		summary.Outputs = append(summary.Outputs, outputSummary)
+	}
+
+	if len(body.Collateral) > 0 {
+		collateral, err := inputSummaries(body.Collateral)
+		if err != nil {
+			return TxSummary{}, err
+		}
+		summary.Collateral = collateral
+
+		// the collateral at risk is the collateral inputs minus the collateral return
+		summary.TotalCollateral = body.TotalCollateral
+		total := uint64(0)
+		for _, input := range body.Collateral {
+			total += uint64(input.Amount)
+		}
+		if body.CollateralReturn != nil {
+			returned := newOutputSummary(body.CollateralReturn.Address.String(), &body.CollateralReturn.Value)
+			summary.CollateralReturn = &returned
+			total -= uint64(body.CollateralReturn.Amount)
+		}
+		if summary.TotalCollateral == 0 {
+			summary.TotalCollateral = total
+		}
+	}
+
+	for policy, assets := range body.Mint {
+		for name, quantity := range assets {
+			if summary.Mint == nil {
+				summary.Mint = map[string]int64{}
+			}
+			summary.Mint[fmt.Sprintf("%x.%x", policy[:], []byte(name))] = quantity
+		}
+	}
+
+	for i := range body.Certificates {
+		certSummary := newCertificateSummary(&body.Certificates[i], p.Protocol)
+		summary.Certificates = append(summary.Certificates, certSummary)
+		summary.Deposits += certSummary.Deposit
+		summary.Refunds += certSummary.Refund
+	}
+	for _, proposal := range body.ProposalProcedures {
+		summary.Deposits += proposal.Deposit
+	}
+	for voter, votes := range body.VotingProcedures {
+		for actionID, procedure := range votes {
+			summary.Votes = append(summary.Votes, VoteSummary{
+				Voter:       fmt.Sprintf("%s %x", voterNames[voter.Type], voter.Hash[:]),
+				GovActionID: fmt.Sprintf("%x#%d", actionID.TxHash[:], actionID.Index),
+				Vote:        voteNames[procedure.Vote],
+			})
+		}
+	}
+	sort.Slice(summary.Votes, func(i, j int) bool {
+		if summary.Votes[i].Voter != summary.Votes[j].Voter {
+			return summary.Votes[i].Voter < summary.Votes[j].Voter
+		}
+		return summary.Votes[i].GovActionID < summary.Votes[j].GovActionID
+	})
+	for _, keyHash := range body.RequiredSigners {
+		summary.RequiredSigners = append(summary.RequiredSigners, hex.EncodeToString(keyHash))
+	}
+
+	return summary, nil
+}
+
+// Validate runs the phase-1 ledger checks on the transaction with the UTxOs and protocol parameters
+// of the package, see Validate.
+func (p *UnsignedTxPackage) Validate(slot uint64) ([]Violation, error) {
+	return Validate(p.Tx, p.UTxOs, p.Protocol, slot)
+}
+
+type utxoCBOR struct {
+	_      struct{} `cbor:",toarray"`
+	Input  *TxInput
+	Output *TxOutput
+}
+
+type networkCBOR struct {
+	_             struct{} `cbor:",toarray"`
+	NetworkId     byte
+	ProtocolMagic uint32
+}
+
+type unsignedTxPackageCBOR struct {
+	_        struct{} `cbor:",toarray"`
+	Version  uint64
+	Tx       []byte
+	UTxOs    []utxoCBOR
+	Network  networkCBOR
+	Protocol protocol.Protocol
+	Summary  TxSummary
+}
+
+// utxoOutput returns the output spent by the input, with its datum if the input was created from the output.
+func utxoOutput(utxo *TxInput) *TxOutput {
+	if utxo.output != nil {
+		return utxo.output
+	}
+	output := NewTxOutputWithValue(utxo.Address, &utxo.Value)
+	output.ScriptRef = utxo.ScriptRef
+	return output
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// The package is encoded as [version, transaction bytes, [[input, output]], [network id, protocol magic],
+// protocol parameters, summary].
+func (p *UnsignedTxPackage) MarshalCBOR() ([]byte, error) {
+	if p.Network == nil {
+		return nil, ErrNoNetwork
+	}
+	txBytes, err := p.Tx.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	summary, err := p.Summary()
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := unsignedTxPackageCBOR{
+		Version:  unsignedTxPackageVersion,
+		Tx:       txBytes,
+		UTxOs:    []utxoCBOR{},
+		Network:  networkCBOR{NetworkId: p.Network.NetworkId, ProtocolMagic: p.Network.ProtocolMagic},
+		Protocol: p.Protocol,
+		Summary:  summary,
+	}
+	for _, utxo := range p.UTxOs {
+		pkg.UTxOs = append(pkg.UTxOs, utxoCBOR{Input: utxo, Output: utxoOutput(utxo)})
+	}
+	return cborEnc.Marshal(pkg)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+// The encoded summary is ignored, since it is computed from the transaction and the UTxOs.
+func (p *UnsignedTxPackage) UnmarshalCBOR(data []byte) error {
+	var pkg unsignedTxPackageCBOR
+	if err := cborDec.Unmarshal(data, &pkg); err != nil {
+		return err
+	}
+	if pkg.Version != unsignedTxPackageVersion {
+		return fmt.Errorf("unsupported unsigned tx package version %d", pkg.Version)
+	}
+
+	tx, err := NewTxFromBytes(pkg.Tx)
+	if err != nil {
+		return err
+	}
+	utxos := []*TxInput{}
+	for i, utxo := range pkg.UTxOs {
+		if utxo.Input == nil || utxo.Output == nil {
+			return fmt.Errorf("utxo %d has no input or output", i)
+		}
+		utxos = append(utxos, NewTxInputFromOutput(hex.EncodeToString(utxo.Input.TxHash), utxo.Input.Index, utxo.Output))
+	}
+	net := &network.NetworkInfo{NetworkId: pkg.Network.NetworkId, ProtocolMagic: pkg.Network.ProtocolMagic}
+
+	*p = *NewUnsignedTxPackage(tx, utxos, net, pkg.Protocol)
+	return nil
+}
+
+type utxoJSON struct {
+	TxIn         string `json:"txIn"`
+	TxOutCBORHex string `json:"txOutCborHex"`
+}
+
+type networkJSON struct {
+	NetworkId     byte   `json:"networkId"`
+	ProtocolMagic uint32 `json:"protocolMagic"`
+}
+
+type unsignedTxPackageJSON struct {
+	Type               string            `json:"type"`
+	Version            uint64            `json:"version"`
+	CBORHex            string            `json:"cborHex"`
+	UTxOs              []utxoJSON        `json:"utxos"`
+	Network            networkJSON       `json:"network"`
+	ProtocolParameters protocol.Protocol `json:"protocolParameters"`
+	Summary            TxSummary         `json:"summary"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The transaction and the UTxOs are hex encoded CBOR, next to their summary and the protocol parameters
+// in the cardano-cli format.
+func (p *UnsignedTxPackage) MarshalJSON() ([]byte, error) {
+	if p.Network == nil {
+		return nil, ErrNoNetwork
+	}
+	txHex, err := p.Tx.Hex()
+	if err != nil {
+		return nil, err
+	}
+	summary, err := p.Summary()
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := unsignedTxPackageJSON{
+		Type:               UnsignedTxPackageType,
+		Version:            unsignedTxPackageVersion,
+		CBORHex:            txHex,
+		UTxOs:              []utxoJSON{},
+		Network:            networkJSON{NetworkId: p.Network.NetworkId, ProtocolMagic: p.Network.ProtocolMagic},
+		ProtocolParameters: p.Protocol,
+		Summary:            summary,
+	}
+	for _, utxo := range p.UTxOs {
+		output, err := utxoOutput(utxo).MarshalCBOR()
+		if err != nil {
+			return nil, err
+		}
+		pkg.UTxOs = append(pkg.UTxOs, utxoJSON{
+			TxIn:         fmt.Sprintf("%x#%d", utxo.TxHash, utxo.Index),
+			TxOutCBORHex: hex.EncodeToString(output),
+		})
+	}
+	return json.MarshalIndent(pkg, "", "    ")
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// The encoded summary is ignored, since it is computed from the transaction and the UTxOs.
+func (p *UnsignedTxPackage) UnmarshalJSON(data []byte) error {
+	var pkg unsignedTxPackageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return err
+	}
+	if pkg.Type != UnsignedTxPackageType {
+		return fmt.Errorf("unexpected type %q, expected %q", pkg.Type, UnsignedTxPackageType)
+	}
+	if pkg.Version != unsignedTxPackageVersion {
+		return fmt.Errorf("unsupported unsigned tx package version %d", pkg.Version)
+	}
+
+	tx, err := NewTxFromHex(pkg.CBORHex)
+	if err != nil {
+		return err
+	}
+	utxos := []*TxInput{}
+	for _, utxo := range pkg.UTxOs {
+		txHash, txIx, found := strings.Cut(utxo.TxIn, "#")
+		if !found {
+			return fmt.Errorf("invalid txIn %q", utxo.TxIn)
+		}
+		index, err := strconv.ParseUint(txIx, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid txIn %q: %w", utxo.TxIn, err)
+		}
+		outputBytes, err := hex.DecodeString(utxo.TxOutCBORHex)
+		if err != nil {
+			return err
+		}
+		output := &TxOutput{}
+		if err := output.UnmarshalCBOR(outputBytes); err != nil {
+			return err
+		}
+		utxos = append(utxos, NewTxInputFromOutput(txHash, uint16(index), output))
+	}
+	net := &network.NetworkInfo{NetworkId: pkg.Network.NetworkId, ProtocolMagic: pkg.Network.ProtocolMagic}
+
+	*p = *NewUnsignedTxPackage(tx, utxos, net, pkg.ProtocolParameters)
+	return nil
+}
+
+// Bytes returns a slice of cbor marshalled bytes.
+func (p *UnsignedTxPackage) Bytes() ([]byte, error) {
+	return p.MarshalCBOR()
+}
+
+// NewUnsignedTxPackageFromBytes returns a pointer to an UnsignedTxPackage decoded from its cbor encoding.
+func NewUnsignedTxPackageFromBytes(data []byte) (*UnsignedTxPackage, error) {
+	p := &UnsignedTxPackage{}
+	if err := p.UnmarshalCBOR(data); err != nil {
+		return nil, fmt.Errorf("cannot deserialize unsigned tx package: %w", err)
+	}
+	return p, nil
+}
+
+// NewUnsignedTxPackageFromJSON returns a pointer to an UnsignedTxPackage decoded from its JSON encoding.
+func NewUnsignedTxPackageFromJSON(data []byte) (*UnsignedTxPackage, error) {
+	p := &UnsignedTxPackage{}
+	if err := p.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("cannot deserialize unsigned tx package: %w", err)
+	}
+	return p, nil
+}
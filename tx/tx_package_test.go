@@ -0,0 +1,327 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsignedTxPackage(t *testing.T) {
+	pr := loadTestProtocol(t)
+	addr, key, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiver := address.NewEnterpriseAddress(network.TestNet(), address.NewKeyStakeCredential(keyHash(1)))
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000)))
+	builder.AddOutputs(tx.NewTxOutput(receiver, 2000000))
+	builder.SetTTL(1000)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := builder.BuildUnsignedPackage(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := pkg.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := pkg.Tx.Body.Fee
+	assert.Equal(t, tx.TxSummary{
+		Inputs: []tx.OutputSummary{{
+			TxIn:     "fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380#0",
+			Address:  addr.String(),
+			Lovelace: 10000000,
+		}},
+		Outputs: []tx.OutputSummary{
+			{Address: receiver.String(), Lovelace: 2000000},
+			{Address: addr.String(), Lovelace: 8000000 - uint(fee), Change: true},
+		},
+		Fee:             fee,
+		Change:          8000000 - uint(fee),
+		TTL:             1000,
+		RequiredSigners: []string{},
+	}, summary)
+
+	hash, err := pkg.Tx.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonData, err := json.Marshal(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := tx.NewUnsignedTxPackageFromJSON(jsonData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cborData, err := pkg.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromCBOR, err := tx.NewUnsignedTxPackageFromBytes(cborData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, decoded := range []*tx.UnsignedTxPackage{fromJSON, fromCBOR} {
+		decodedHash, err := decoded.Tx.Hash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, hash, decodedHash)
+		assert.Equal(t, network.TestNet(), decoded.Network)
+		assert.Equal(t, pr, decoded.Protocol)
+
+		decodedSummary, err := decoded.Summary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, summary, decodedSummary)
+
+		// the offline signer validates and signs the transaction
+		violations, err := decoded.Validate(500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []tx.ViolationType{tx.ViolationMissingWitness}, violationTypes(violations))
+
+		witnessSet, err := decoded.Tx.SignWitnessSet(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := decoded.Tx.AddWitnesses(witnessSet); err != nil {
+			t.Fatal(err)
+		}
+		violations, err = decoded.Validate(500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, violations)
+	}
+
+	// the summary is not trusted, but computed from the UTxOs
+	var encoded map[string]interface{}
+	if err := json.Unmarshal(jsonData, &encoded); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tx.UnsignedTxPackageType, encoded["type"])
+	encoded["summary"] = map[string]interface{}{"fee": 1}
+	tampered, err := json.Marshal(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromTampered, err := tx.NewUnsignedTxPackageFromJSON(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperedSummary, err := fromTampered.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, summary, tamperedSummary)
+
+	_, err = tx.NewUnsignedTxPackage(pkg.Tx, nil, network.TestNet(), pr).Summary()
+	assert.ErrorIs(t, err, tx.ErrUnresolvedInput)
+
+	// the JSON encoding is versioned like the CBOR encoding
+	assert.Equal(t, float64(1), encoded["version"])
+	encoded["version"] = 2
+	unsupported, err := json.Marshal(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.NewUnsignedTxPackageFromJSON(unsupported)
+	assert.Error(t, err)
+
+	noNetwork := tx.NewUnsignedTxPackage(pkg.Tx, pkg.UTxOs, nil, pr)
+	_, err = noNetwork.Bytes()
+	assert.ErrorIs(t, err, tx.ErrNoNetwork)
+	_, err = json.Marshal(noNetwork)
+	assert.ErrorIs(t, err, tx.ErrNoNetwork)
+}
+
+func TestUnsignedTxPackageUTxOs(t *testing.T) {
+	pr := loadTestProtocol(t)
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	datum := tx.NewIntegerPlutusData(42)
+	datumHash := make([]byte, 32)
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0,
+		tx.NewTxOutputWithDatumHash(addr, tx.NewValue(10000000), datumHash),
+	))
+	builder.AddReferenceInputs(tx.NewTxInputFromOutput(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1,
+		tx.NewTxOutputWithInlineDatum(addr, tx.NewValue(2000000), datum),
+	))
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := builder.BuildUnsignedPackage(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the UTxOs are the spent and referenced outputs, with their datums
+	jsonData, err := json.Marshal(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encoded struct {
+		UTxOs []struct {
+			TxOutCBORHex string `json:"txOutCborHex"`
+		} `json:"utxos"`
+	}
+	if err := json.Unmarshal(jsonData, &encoded); err != nil {
+		t.Fatal(err)
+	}
+	outputs := []*tx.TxOutput{}
+	for _, utxo := range encoded.UTxOs {
+		data, err := hex.DecodeString(utxo.TxOutCBORHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output := &tx.TxOutput{}
+		if err := output.UnmarshalCBOR(data); err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, output)
+	}
+	if assert.Len(t, outputs, 2) {
+		assert.Equal(t, datumHash, outputs[0].DatumHash)
+		if assert.NotNil(t, outputs[1].Datum) {
+			assert.Equal(t, datum.Bytes(), outputs[1].Datum.Bytes())
+		}
+	}
+
+	cborData, err := pkg.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewUnsignedTxPackageFromBytes(cborData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reencoded, err := decoded.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, cborData, reencoded)
+
+	// a package whose UTxOs are null is rejected
+	var fields []cbor.RawMessage
+	if err := cbor.Unmarshal(cborData, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields[2] = cbor.RawMessage{0x81, 0x82, 0xf6, 0xf6}
+	nullUTxOs, err := cbor.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.NewUnsignedTxPackageFromBytes(nullUTxOs)
+	assert.Error(t, err)
+
+	// inputs without an address cannot be shown to the signer
+	builder = tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, 10000000))
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	_, err = builder.BuildUnsignedPackage(network.TestNet())
+	assert.ErrorIs(t, err, tx.ErrUnresolvedInput)
+}
+
+func TestUnsignedTxPackageSummaryOfScriptsAndGovernance(t *testing.T) {
+	pr := loadTestProtocol(t)
+	pr.CollateralPercentage = 150
+	pr.MaxCollateralInputs = 3
+	addr, key, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signerHash := key.Public().PublicKey().Hash()
+	policy, err := tx.NewScriptPubKey(signerHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := policy.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stake := address.NewKeyStakeCredential(signerHash[:])
+	drepHash := keyHash(2)
+	actionTxHash := make([]byte, 32)
+	actionTxHash[0] = 0xab
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000)))
+	if err := builder.Mint(policy, tx.NewMintAsset("new", 100)); err != nil {
+		t.Fatal(err)
+	}
+	builder.AddCertificates(tx.NewRegistrationCertificate(stake, 2000000))
+	builder.AddVote(tx.NewVoter(tx.VoterDRepKey, drepHash[:]), tx.NewGovActionID(actionTxHash, 1), tx.VoteNo, nil)
+	builder.SetCollateral([]*tx.TxInput{
+		tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 1, tx.NewTxOutput(addr, 5000000)),
+	}, addr)
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := builder.BuildUnsignedPackage(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := pkg.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]int64{hex.EncodeToString(hash) + "." + hex.EncodeToString([]byte("new")): 100}, summary.Mint)
+	assert.Equal(t, []tx.OutputSummary{{
+		TxIn:     "fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380#1",
+		Address:  addr.String(),
+		Lovelace: 5000000,
+	}}, summary.Collateral)
+	body := pkg.Tx.Body
+	if assert.NotNil(t, body.CollateralReturn) && assert.NotNil(t, summary.CollateralReturn) {
+		assert.Equal(t, tx.OutputSummary{Address: addr.String(), Lovelace: body.CollateralReturn.Amount}, *summary.CollateralReturn)
+		assert.Equal(t, uint64(5000000-body.CollateralReturn.Amount), summary.TotalCollateral)
+	}
+	assert.Equal(t, []tx.CertificateSummary{{
+		Type:       "registration",
+		Credential: "keyHash " + hex.EncodeToString(signerHash[:]),
+		Deposit:    2000000,
+	}}, summary.Certificates)
+	assert.Equal(t, uint(2000000), summary.Deposits)
+	assert.Equal(t, []tx.VoteSummary{{
+		Voter:       "drepKeyHash " + hex.EncodeToString(drepHash[:]),
+		GovActionID: hex.EncodeToString(actionTxHash) + "#1",
+		Vote:        "no",
+	}}, summary.Votes)
+
+	// the collateral inputs are resolved from the UTxOs like the spent inputs
+	utxos := []*tx.TxInput{}
+	for _, utxo := range pkg.UTxOs {
+		if utxo.Index != 1 {
+			utxos = append(utxos, utxo)
+		}
+	}
+	_, err = tx.NewUnsignedTxPackage(pkg.Tx, utxos, network.TestNet(), pr).Summary()
+	assert.ErrorIs(t, err, tx.ErrUnresolvedInput)
+}
@@ -1,13 +1,31 @@
 package tx
 
 import (
+	"bytes"
 	"crypto/ed25519"
+	"errors"
 	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/milos-ethernal/go-cardano-serialization/address"
 )
 
+var ErrInvalidWitness = errors.New("invalid witness")
+
 type WitnessSet struct {
-	Witnesses []VKeyWitness  `cbor:"0,keyasint,omitempty"`
-	Scripts   []NativeScript `cbor:"1,keyasint,omitempty"`
+	Witnesses          []VKeyWitness      `cbor:"0,keyasint,omitempty"`
+	Scripts            []NativeScript     `cbor:"1,keyasint,omitempty"`
+	BootstrapWitnesses []BootstrapWitness `cbor:"2,keyasint,omitempty"`
+	PlutusV1Scripts    [][]byte           `cbor:"3,keyasint,omitempty"`
+	PlutusData         []PlutusData       `cbor:"4,keyasint,omitempty"`
+	Redeemers          Redeemers          `cbor:"5,keyasint,omitempty"`
+	PlutusV2Scripts    [][]byte           `cbor:"6,keyasint,omitempty"`
+	PlutusV3Scripts    [][]byte           `cbor:"7,keyasint,omitempty"`
+
+	raw rawBytes
+	// fields keeps the original encoding of each field by key, so that adding witnesses to a decoded
+	// witness set leaves the Plutus data and redeemers hashed by the script data hash untouched.
+	fields map[uint64]rawBytes
 }
 
 // NewTXWitness returns a pointer to a Witness created from VKeyWitnesses.
@@ -18,6 +36,181 @@ func NewTXWitnessSet(scripts []NativeScript, witnesses []VKeyWitness) *WitnessSe
 	}
 }
 
+// NewWitnessSetFromBytes returns a pointer to a WitnessSet decoded from its cbor encoding,
+// e.g. the witnesses of a party signing a multisig transaction.
+func NewWitnessSetFromBytes(data []byte) (*WitnessSet, error) {
+	w := &WitnessSet{}
+	if err := cborDec.Unmarshal(data, w); err != nil {
+		return nil, fmt.Errorf("cannot deserialize witness set: %w", err)
+	}
+	return w, nil
+}
+
+// Bytes returns a slice of cbor marshalled bytes.
+func (w *WitnessSet) Bytes() ([]byte, error) {
+	return w.MarshalCBOR()
+}
+
+// Merge adds the vkey witnesses, bootstrap witnesses and native scripts of other to the witness set.
+// Witnesses of keys that already signed and scripts that are already present are skipped.
+func (w *WitnessSet) Merge(other *WitnessSet) error {
+	for _, witness := range other.Witnesses {
+		found := false
+		for _, existing := range w.Witnesses {
+			if bytes.Equal(existing.VKey, witness.VKey) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			w.Witnesses = append(w.Witnesses, witness)
+		}
+	}
+
+	for _, witness := range other.BootstrapWitnesses {
+		found := false
+		for _, existing := range w.BootstrapWitnesses {
+			if bytes.Equal(existing.VKey, witness.VKey) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			w.BootstrapWitnesses = append(w.BootstrapWitnesses, witness)
+		}
+	}
+
+	hashes := map[string]bool{}
+	for _, script := range w.Scripts {
+		hash, err := script.Hash()
+		if err != nil {
+			return err
+		}
+		hashes[string(hash)] = true
+	}
+	for _, script := range other.Scripts {
+		hash, err := script.Hash()
+		if err != nil {
+			return err
+		}
+		if !hashes[string(hash)] {
+			hashes[string(hash)] = true
+			w.Scripts = append(w.Scripts, script)
+		}
+	}
+
+	return nil
+}
+
+// verify returns ErrInvalidWitness if a signature of the vkey or bootstrap witnesses is not valid for the transaction hash.
+func (w *WitnessSet) verify(txHash [32]byte) error {
+	for _, witness := range w.Witnesses {
+		if !witness.Verify(txHash) {
+			return fmt.Errorf("%w: signature of vkey %x", ErrInvalidWitness, witness.VKey)
+		}
+	}
+	for _, witness := range w.BootstrapWitnesses {
+		if !witness.Verify(txHash) {
+			return fmt.Errorf("%w: signature of bootstrap vkey %x", ErrInvalidWitness, witness.VKey)
+		}
+	}
+	return nil
+}
+
+// PlutusScripts returns the Plutus scripts of all versions in the witness set.
+func (w *WitnessSet) PlutusScripts() []PlutusScript {
+	scripts := []PlutusScript{}
+	for _, s := range w.PlutusV1Scripts {
+		scripts = append(scripts, NewPlutusScript(PlutusV1, s))
+	}
+	for _, s := range w.PlutusV2Scripts {
+		scripts = append(scripts, NewPlutusScript(PlutusV2, s))
+	}
+	for _, s := range w.PlutusV3Scripts {
+		scripts = append(scripts, NewPlutusScript(PlutusV3, s))
+	}
+	return scripts
+}
+
+// AddPlutusScript adds the script to the witness set, unless it is already present.
+func (w *WitnessSet) AddPlutusScript(script PlutusScript) {
+	var scripts *[][]byte
+	switch script.Version {
+	case PlutusV1:
+		scripts = &w.PlutusV1Scripts
+	case PlutusV2:
+		scripts = &w.PlutusV2Scripts
+	default:
+		scripts = &w.PlutusV3Scripts
+	}
+
+	for _, s := range *scripts {
+		if bytes.Equal(s, script.Script) {
+			return
+		}
+	}
+	*scripts = append(*scripts, script.Script)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// A decoded witness set that has not been modified is returned with its original bytes,
+// otherwise only the modified fields are encoded again.
+func (w *WitnessSet) MarshalCBOR() ([]byte, error) {
+	type witnessSet WitnessSet
+
+	enc, err := cbor.Marshal(witnessSet(*w))
+	if err != nil {
+		return nil, err
+	}
+	if w.raw.raw == nil || bytes.Equal(w.raw.enc, enc) {
+		return w.raw.bytes(enc), nil
+	}
+
+	fields := map[uint64]cbor.RawMessage{}
+	if err := cborDec.Unmarshal(enc, &fields); err != nil {
+		return nil, err
+	}
+	for key, field := range fields {
+		raw := w.fields[key]
+		fields[key] = raw.bytes(field)
+	}
+	return cborEnc.Marshal(fields)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (w *WitnessSet) UnmarshalCBOR(data []byte) error {
+	type witnessSet WitnessSet
+
+	var ws witnessSet
+	if err := cborDec.Unmarshal(data, &ws); err != nil {
+		return err
+	}
+	*w = WitnessSet(ws)
+
+	enc, err := cbor.Marshal(ws)
+	if err != nil {
+		return err
+	}
+	w.raw.keep(data, enc)
+
+	rawFields := map[uint64]cbor.RawMessage{}
+	if err := cborDec.Unmarshal(data, &rawFields); err != nil {
+		return err
+	}
+	encFields := map[uint64]cbor.RawMessage{}
+	if err := cborDec.Unmarshal(enc, &encFields); err != nil {
+		return err
+	}
+	w.fields = map[uint64]rawBytes{}
+	for key, field := range encFields {
+		raw := rawBytes{}
+		raw.keep(rawFields[key], field)
+		w.fields[key] = raw
+	}
+
+	return nil
+}
+
 // VKeyWitness - Witness for use with Shelley based transactions
 type VKeyWitness struct {
 	_         struct{} `cbor:",toarray"`
@@ -32,6 +225,11 @@ func NewVKeyWitness(vkey, signature []byte) VKeyWitness {
 	}
 }
 
+// Verify reports whether the witness signature of the transaction hash is valid.
+func (w VKeyWitness) Verify(txHash [32]byte) bool {
+	return len(w.VKey) == ed25519.PublicKeySize && ed25519.Verify(w.VKey, txHash[:], w.Signature)
+}
+
 // BootstrapWitness for use with Byron/Legacy based transactions
 type BootstrapWitness struct {
 	_          struct{} `cbor:",toarray"`
@@ -41,6 +239,40 @@ type BootstrapWitness struct {
 	Attributes []byte
 }
 
+// NewBootstrapWitness creates a Witness for spending from the Byron address from a verification key,
+// its chain code and the transaction signature.
+func NewBootstrapWitness(vkey, signature, chainCode []byte, addr *address.ByronAddress) (BootstrapWitness, error) {
+	attributes, err := addr.AttributesBytes()
+	if err != nil {
+		return BootstrapWitness{}, err
+	}
+	return BootstrapWitness{
+		VKey:       vkey,
+		Signature:  signature,
+		ChainCode:  chainCode,
+		Attributes: attributes,
+	}, nil
+}
+
+// Verify reports whether the witness signature of the transaction hash is valid.
+func (w BootstrapWitness) Verify(txHash [32]byte) bool {
+	return len(w.VKey) == ed25519.PublicKeySize && ed25519.Verify(w.VKey, txHash[:], w.Signature)
+}
+
+// KeyHash returns the root of the Byron address of the witness, which the ledger uses as the key hash
+// of bootstrap witnesses when checking native scripts and required signers.
+func (w BootstrapWitness) KeyHash() (AddrKeyHash, error) {
+	var attributes address.ByronAddressAttributes
+	if err := cborDec.Unmarshal(w.Attributes, &attributes); err != nil {
+		return nil, fmt.Errorf("%w: attributes of bootstrap vkey %x: %v", ErrInvalidWitness, w.VKey, err)
+	}
+	addr, err := address.NewByronAddress(w.VKey, w.ChainCode, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return addr.Hash, nil
+}
+
 // GetVerificationKeyFromSigningKey retrieves verification/public key from signing/private key
 func GetVerificationKeyFromSigningKey(signingKey []byte) []byte {
 	return ed25519.NewKeyFromSeed(signingKey).Public().(ed25519.PublicKey)
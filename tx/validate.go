@@ -0,0 +1,407 @@
+package tx
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+	"github.com/milos-ethernal/go-cardano-serialization/protocol"
+)
+
+// ViolationType is the phase-1 ledger rule violated by a transaction.
+type ViolationType int
+
+const (
+	ViolationNoInputs ViolationType = iota
+	ViolationInputNotFound
+	ViolationValueNotConserved
+	ViolationFeeTooSmall
+	ViolationOutputTooSmall
+	ViolationOutputValueTooLarge
+	ViolationTxTooLarge
+	ViolationOutsideValidityInterval
+	ViolationCollateral
+	ViolationMissingWitness
+	ViolationInvalidSignature
+	ViolationNativeScriptFailed
+)
+
+var violationNames = map[ViolationType]string{
+	ViolationNoInputs:                "no inputs",
+	ViolationInputNotFound:           "input not found",
+	ViolationValueNotConserved:       "value not conserved",
+	ViolationFeeTooSmall:             "fee too small",
+	ViolationOutputTooSmall:          "output too small",
+	ViolationOutputValueTooLarge:     "output value too large",
+	ViolationTxTooLarge:              "transaction too large",
+	ViolationOutsideValidityInterval: "outside validity interval",
+	ViolationCollateral:              "invalid collateral",
+	ViolationMissingWitness:          "missing witness",
+	ViolationInvalidSignature:        "invalid signature",
+	ViolationNativeScriptFailed:      "native script failed",
+}
+
+func (t ViolationType) String() string {
+	if name, ok := violationNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ViolationType(%d)", int(t))
+}
+
+// Violation is a ledger rule the transaction does not satisfy.
+type Violation struct {
+	Type    ViolationType
+	Message string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Type, v.Message)
+}
+
+// validator collects the violations of a transaction resolved against the UTxO set.
+type validator struct {
+	tb         *TxBuilder
+	slot       uint64
+	violations []Violation
+}
+
+// Validate runs the phase-1 ledger checks on the transaction as if it was submitted at the slot:
+// input existence, value preservation, minimum fee, minimum lovelace and maximum value size of the outputs,
+// maximum transaction size, validity interval, collateral, witness completeness, native script evaluation
+// and signature validity.
+// The inputs, collateral inputs and reference inputs are resolved from utxos.
+// It returns the violated rules, or none if the transaction is expected to pass phase-1 validation.
+// Plutus scripts are not run.
+func Validate(tx *Tx, utxos []*TxInput, pr protocol.Protocol, slot uint64) ([]Violation, error) {
+	v := &validator{slot: slot}
+	v.tb = NewTxBuilder(pr, nil)
+	v.tb.tx = v.resolve(tx, utxos)
+
+	if err := v.checkSize(); err != nil {
+		return nil, err
+	}
+	v.checkBalance()
+	if err := v.checkFee(); err != nil {
+		return nil, err
+	}
+	if err := v.checkOutputs(); err != nil {
+		return nil, err
+	}
+	v.checkValidityInterval()
+	v.checkCollateral()
+	if err := v.checkWitnesses(); err != nil {
+		return nil, err
+	}
+	if err := v.checkSignatures(); err != nil {
+		return nil, err
+	}
+
+	return v.violations, nil
+}
+
+func (v *validator) add(violationType ViolationType, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Type: violationType, Message: fmt.Sprintf(format, args...)})
+}
+
+// resolve returns a copy of the transaction spending and referencing the outputs of utxos.
+func (v *validator) resolve(tx *Tx, utxos []*TxInput) *Tx {
+	if len(tx.Body.Inputs) == 0 {
+		v.add(ViolationNoInputs, "the transaction spends no inputs")
+	}
+	resolved, missing := resolveTx(tx, utxos)
+	for _, input := range missing {
+		v.add(ViolationInputNotFound, "input %x#%d is not in the UTxO set", input.TxHash, input.Index)
+	}
+	return resolved
+}
+
+// resolveTx returns a copy of the transaction whose inputs, collateral inputs and reference inputs are
+// the outputs of utxos, and the inputs missing from utxos, which are kept as they are.
+func resolveTx(tx *Tx, utxos []*TxInput) (*Tx, []*TxInput) {
+	outputs := map[string]*TxInput{}
+	for _, utxo := range utxos {
+		outputs[fmt.Sprintf("%x#%d", utxo.TxHash, utxo.Index)] = utxo
+	}
+	missing := []*TxInput{}
+	resolveInputs := func(inputs []*TxInput) []*TxInput {
+		resolved := make([]*TxInput, len(inputs))
+		for i, input := range inputs {
+			utxo, ok := outputs[fmt.Sprintf("%x#%d", input.TxHash, input.Index)]
+			if !ok {
+				missing = append(missing, input)
+				utxo = input
+			}
+			resolved[i] = utxo
+		}
+		return resolved
+	}
+
+	body := *tx.Body
+	body.Inputs = resolveInputs(body.Inputs)
+	body.Collateral = resolveInputs(body.Collateral)
+	body.ReferenceInputs = resolveInputs(body.ReferenceInputs)
+
+	witnessSet := &WitnessSet{}
+	if tx.WitnessSet != nil {
+		witnessSet = tx.WitnessSet
+	}
+
+	return &Tx{
+		Body:          &body,
+		WitnessSet:    witnessSet,
+		Valid:         tx.Valid,
+		AuxiliaryData: tx.AuxiliaryData,
+	}, missing
+}
+
+func (v *validator) checkSize() error {
+	data, err := v.tb.tx.Bytes()
+	if err != nil {
+		return err
+	}
+	if max := v.tb.protocol.MaxTxSize; max > 0 && uint(len(data)) > max {
+		v.add(ViolationTxTooLarge, "the transaction is %d bytes, more than the maximum of %d bytes", len(data), max)
+	}
+	return nil
+}
+
+// checkBalance verifies that the value consumed by the transaction equals the value produced, including the fee.
+func (v *validator) checkBalance() {
+	consumed, produced := v.tb.balance()
+	produced.Amount += uint(v.tb.tx.Body.Fee)
+
+	diff, err := consumed.Sub(produced)
+	if err != nil || !diff.IsZero() {
+		v.add(ViolationValueNotConserved, "consumed %d lovelace and %d assets, produced %d lovelace and %d assets",
+			consumed.Amount, countAssets(consumed.MultiAsset), produced.Amount, countAssets(produced.MultiAsset))
+	}
+}
+
+// countAssets returns the number of distinct tokens held by the multi asset.
+func countAssets(ma MultiAsset) int {
+	n := 0
+	for _, assets := range ma {
+		for _, quantity := range assets {
+			if quantity > 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func (v *validator) checkFee() error {
+	minFee, err := v.tb.tx.Fee(v.tb.feeCalculator())
+	if err != nil {
+		return err
+	}
+	if fee := uint(v.tb.tx.Body.Fee); fee < minFee {
+		v.add(ViolationFeeTooSmall, "fee of %d lovelace is less than the minimum fee of %d lovelace", fee, minFee)
+	}
+	return nil
+}
+
+// checkOutputs verifies the minimum lovelace and the maximum value size of the outputs and the collateral return.
+func (v *validator) checkOutputs() error {
+	outputs := v.tb.tx.Body.Outputs
+	if v.tb.tx.Body.CollateralReturn != nil {
+		outputs = append(outputs[:len(outputs):len(outputs)], v.tb.tx.Body.CollateralReturn)
+	}
+
+	for i, output := range outputs {
+		name := fmt.Sprintf("output %d", i)
+		if i == len(v.tb.tx.Body.Outputs) {
+			name = "collateral return"
+		}
+
+		minAmount, err := minOutputAmount(output, v.tb.protocol)
+		if err != nil {
+			return err
+		}
+		if output.Amount < minAmount {
+			v.add(ViolationOutputTooSmall, "%s holds %d lovelace, less than the minimum of %d lovelace", name, output.Amount, minAmount)
+		}
+
+		if max := v.tb.protocol.MaxValueSize; max > 0 {
+			value, err := output.Value.MarshalCBOR()
+			if err != nil {
+				return err
+			}
+			if uint(len(value)) > max {
+				v.add(ViolationOutputValueTooLarge, "value of %s is %d bytes, more than the maximum of %d bytes", name, len(value), max)
+			}
+		}
+	}
+	return nil
+}
+
+// minOutputAmount returns the minimum lovelace of the output: (160 + output size) * utxoCostPerByte,
+// or the deprecated minUTxOValue if the protocol parameters have no utxoCostPerByte.
+func minOutputAmount(output *TxOutput, pr protocol.Protocol) (uint, error) {
+	if pr.UTxOCostPerByte == 0 {
+		return pr.MinUTXOValue, nil
+	}
+	data, err := output.MarshalCBOR()
+	if err != nil {
+		return 0, err
+	}
+	return (160 + uint(len(data))) * pr.UTxOCostPerByte, nil
+}
+
+func (v *validator) checkValidityInterval() {
+	body := v.tb.tx.Body
+	if body.ValidityIntervalStart != 0 && v.slot < body.ValidityIntervalStart {
+		v.add(ViolationOutsideValidityInterval, "slot %d is before the validity interval start %d", v.slot, body.ValidityIntervalStart)
+	}
+	if body.TTL != 0 && v.slot >= body.TTL {
+		v.add(ViolationOutsideValidityInterval, "slot %d is not before the TTL %d", v.slot, body.TTL)
+	}
+}
+
+// checkCollateral verifies the collateral of transactions running Plutus scripts.
+func (v *validator) checkCollateral() {
+	body := v.tb.tx.Body
+	if len(v.tb.tx.WitnessSet.Redeemers) == 0 {
+		return
+	}
+	if len(body.Collateral) == 0 {
+		v.add(ViolationCollateral, "the transaction runs Plutus scripts without collateral inputs")
+		return
+	}
+	if max := v.tb.protocol.MaxCollateralInputs; max > 0 && uint(len(body.Collateral)) > max {
+		v.add(ViolationCollateral, "%d collateral inputs exceed the maximum of %d", len(body.Collateral), max)
+	}
+
+	collateral := NewValue(0)
+	for _, input := range body.Collateral {
+		if input.Address != nil && !isKeyLocked(input.Address) {
+			v.add(ViolationCollateral, "collateral input %x#%d is locked by a script", input.TxHash, input.Index)
+		}
+		collateral = collateral.Add(&input.Value)
+	}
+	if body.CollateralReturn != nil {
+		balance, err := collateral.Sub(&body.CollateralReturn.Value)
+		if err != nil {
+			v.add(ViolationCollateral, "collateral return exceeds the collateral inputs: %v", err)
+			return
+		}
+		collateral = balance
+	}
+
+	if !collateral.MultiAsset.IsZero() {
+		v.add(ViolationCollateral, "collateral holds tokens that are not returned by the collateral return")
+	}
+	// balance * 100 >= fee * collateralPercentage
+	if collateral.Amount*100 < uint(body.Fee)*v.tb.protocol.CollateralPercentage {
+		v.add(ViolationCollateral, "collateral of %d lovelace is less than %d%% of the fee", collateral.Amount, v.tb.protocol.CollateralPercentage)
+	}
+	if body.TotalCollateral != 0 && body.TotalCollateral != uint64(collateral.Amount) {
+		v.add(ViolationCollateral, "total collateral of %d lovelace does not match the collateral balance of %d lovelace", body.TotalCollateral, collateral.Amount)
+	}
+}
+
+// checkWitnesses verifies that every required key signed the transaction and that every required script
+// is included in the witness set or referenced by an input.
+func (v *validator) checkWitnesses() error {
+	tx := v.tb.tx
+
+	signers := map[string]bool{}
+	keyHashes := []AddrKeyHash{}
+	for _, witness := range tx.WitnessSet.Witnesses {
+		keyHash, err := Blake224Hash(witness.VKey)
+		if err != nil {
+			return err
+		}
+		signers[string(keyHash)] = true
+		keyHashes = append(keyHashes, keyHash)
+	}
+	for _, witness := range tx.WitnessSet.BootstrapWitnesses {
+		keyHash, err := witness.KeyHash()
+		if err != nil {
+			return err
+		}
+		signers[string(keyHash)] = true
+		keyHashes = append(keyHashes, keyHash)
+	}
+
+	for _, script := range tx.WitnessSet.Scripts {
+		if !script.Evaluate(keyHashes, tx.Body.ValidityIntervalStart, tx.Body.TTL) {
+			hash, err := script.Hash()
+			if err != nil {
+				return err
+			}
+			v.add(ViolationNativeScriptFailed, "native script %x is not satisfied", hash)
+		}
+	}
+	scripts, err := tx.scriptHashes()
+	if err != nil {
+		return err
+	}
+
+	creds, err := v.tb.requiredCredentials()
+	if err != nil {
+		return err
+	}
+	for _, input := range tx.Body.Inputs {
+		if input.Address == nil {
+			continue
+		}
+		if cred := paymentCredential(input.Address); cred != nil && cred.Kind == address.ScriptStakeCredentialType {
+			creds = append(creds, cred)
+		}
+	}
+	for policy := range tx.Body.Mint {
+		policy := policy
+		creds = append(creds, address.NewScriptStakeCredential(policy[:]))
+	}
+
+	missing := map[string]bool{}
+	for _, cred := range creds {
+		switch {
+		case missing[string(cred.Payload)]:
+		case cred.Kind == address.KeyStakeCredentialType && !signers[string(cred.Payload)]:
+			v.add(ViolationMissingWitness, "no vkey witness for key hash %x", cred.Payload)
+			missing[string(cred.Payload)] = true
+		case cred.Kind == address.ScriptStakeCredentialType && !scripts[string(cred.Payload)]:
+			v.add(ViolationMissingWitness, "no script for script hash %x", cred.Payload)
+			missing[string(cred.Payload)] = true
+		}
+	}
+
+	for _, addr := range v.tb.byronInputs() {
+		found := false
+		for _, witness := range tx.WitnessSet.BootstrapWitnesses {
+			witnessAddr, err := address.NewByronAddress(witness.VKey, witness.ChainCode, addr.Attributes)
+			if err == nil && bytes.Equal(witnessAddr.Hash, addr.Hash) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(ViolationMissingWitness, "no bootstrap witness for byron address %s", addr)
+		}
+	}
+
+	return nil
+}
+
+// checkSignatures verifies the signatures of the vkey and bootstrap witnesses.
+func (v *validator) checkSignatures() error {
+	hash, err := v.tb.tx.Hash()
+	if err != nil {
+		return err
+	}
+
+	for _, witness := range v.tb.tx.WitnessSet.Witnesses {
+		if !witness.Verify(hash) {
+			v.add(ViolationInvalidSignature, "signature of vkey %x is invalid", witness.VKey)
+		}
+	}
+	for _, witness := range v.tb.tx.WitnessSet.BootstrapWitnesses {
+		if !witness.Verify(hash) {
+			v.add(ViolationInvalidSignature, "signature of bootstrap vkey %x is invalid", witness.VKey)
+		}
+	}
+
+	return nil
+}
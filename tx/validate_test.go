@@ -0,0 +1,172 @@
+package tx_test
+
+import (
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func violationTypes(violations []tx.Violation) []tx.ViolationType {
+	types := []tx.ViolationType{}
+	for _, violation := range violations {
+		types = append(types, violation.Type)
+	}
+	return types
+}
+
+func TestValidate(t *testing.T) {
+	addr, key, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr := loadTestProtocol(t)
+	utxos := []*tx.TxInput{
+		tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000)),
+	}
+
+	build := func(outputAmount uint, addChange bool, keys ...bip32.XPrv) *tx.Tx {
+		builder := tx.NewTxBuilder(pr, keys)
+		builder.AddInputs(utxos...)
+		builder.AddOutputs(tx.NewTxOutput(addr, outputAmount))
+		builder.SetTTL(1000)
+		if addChange {
+			if err := builder.AddChangeIfNeeded(addr); err != nil {
+				t.Fatal(err)
+			}
+		}
+		built, err := builder.Build()
+		if err != nil && addChange {
+			t.Fatal(err)
+		}
+		return &built
+	}
+	decode := func(txFinal *tx.Tx) *tx.Tx {
+		data, err := txFinal.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := tx.NewTxFromBytes(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return decoded
+	}
+
+	valid := build(2000000, true, key)
+
+	scenarios := []struct {
+		description string
+		tx          *tx.Tx
+		utxos       []*tx.TxInput
+		slot        uint64
+		expected    []tx.ViolationType
+	}{
+		{
+			description: "valid transaction",
+			tx:          valid,
+			utxos:       utxos,
+			slot:        500,
+			expected:    []tx.ViolationType{},
+		},
+		{
+			description: "decoded transaction",
+			tx:          decode(valid),
+			utxos:       utxos,
+			slot:        500,
+			expected:    []tx.ViolationType{},
+		},
+		{
+			description: "unknown input",
+			tx:          decode(valid),
+			utxos:       []*tx.TxInput{},
+			slot:        500,
+			expected: []tx.ViolationType{
+				tx.ViolationInputNotFound,
+				tx.ViolationValueNotConserved,
+			},
+		},
+		{
+			description: "expired",
+			tx:          valid,
+			utxos:       utxos,
+			slot:        1000,
+			expected:    []tx.ViolationType{tx.ViolationOutsideValidityInterval},
+		},
+		{
+			description: "modified output",
+			tx: func() *tx.Tx {
+				decoded := decode(valid)
+				decoded.Body.Outputs[0].Amount++
+				return decoded
+			}(),
+			utxos:    utxos,
+			slot:     500,
+			expected: []tx.ViolationType{tx.ViolationValueNotConserved, tx.ViolationInvalidSignature},
+		},
+		{
+			description: "unsigned",
+			tx: func() *tx.Tx {
+				decoded := decode(valid)
+				decoded.WitnessSet.Witnesses = nil
+				return decoded
+			}(),
+			utxos:    utxos,
+			slot:     500,
+			expected: []tx.ViolationType{tx.ViolationMissingWitness},
+		},
+		{
+			description: "without change",
+			tx:          build(2000000, false, key),
+			utxos:       utxos,
+			slot:        500,
+			expected:    []tx.ViolationType{tx.ViolationValueNotConserved, tx.ViolationFeeTooSmall},
+		},
+		{
+			description: "output below the minimum",
+			tx:          build(1000, true, key),
+			utxos:       utxos,
+			slot:        500,
+			expected:    []tx.ViolationType{tx.ViolationOutputTooSmall},
+		},
+	}
+
+	for _, sc := range scenarios {
+		t.Run(sc.description, func(t *testing.T) {
+			violations, err := tx.Validate(sc.tx, sc.utxos, pr, sc.slot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, sc.expected, violationTypes(violations), violations)
+		})
+	}
+
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{key})
+	builder.AddInputs(utxos...)
+	builder.AddOutputs(tx.NewTxOutput(addr, 2000000))
+	if err := builder.AddNativeScripts(tx.NativeScript{Type: tx.ScriptPubKey, KeyHash: make([]byte, 28)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	withScript, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := tx.Validate(&withScript, utxos, pr, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []tx.ViolationType{tx.ViolationNativeScriptFailed}, violationTypes(violations))
+
+	small := pr
+	small.MaxTxSize = 100
+	violations, err = tx.Validate(valid, utxos, small, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []tx.ViolationType{tx.ViolationTxTooLarge}, violationTypes(violations))
+}
@@ -0,0 +1,241 @@
+package tx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/milos-ethernal/go-cardano-serialization/crypto"
+)
+
+var (
+	ErrInsufficientValue = errors.New("insufficient value")
+)
+
+// AssetName is the name of a native token (raw bytes, at most 32).
+type AssetName string
+
+// Assets maps asset names to quantities.
+type Assets map[AssetName]uint64
+
+// MultiAsset maps policy ids to the assets of the policy.
+type MultiAsset map[crypto.ScriptHash]Assets
+
+// Value is an amount of lovelace together with native tokens.
+type Value struct {
+	// Amount in lovelace
+	Amount     uint
+	MultiAsset MultiAsset
+}
+
+// NewValue returns a pointer to a new Value of amount lovelace.
+func NewValue(amount uint) *Value {
+	return &Value{
+		Amount:     amount,
+		MultiAsset: MultiAsset{},
+	}
+}
+
+// NewValueWithAssets returns a pointer to a new Value of amount lovelace and native tokens.
+func NewValueWithAssets(amount uint, assets MultiAsset) *Value {
+	return &Value{
+		Amount:     amount,
+		MultiAsset: assets.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the value.
+func (v *Value) Clone() *Value {
+	return NewValueWithAssets(v.Amount, v.MultiAsset)
+}
+
+// IsZero reports whether the value holds neither lovelace nor tokens.
+func (v *Value) IsZero() bool {
+	return v.Amount == 0 && v.MultiAsset.IsZero()
+}
+
+// Add returns a new Value holding the sum of both values.
+func (v *Value) Add(other *Value) *Value {
+	sum := v.Clone()
+	sum.Amount += other.Amount
+	for policy, assets := range other.MultiAsset {
+		for name, quantity := range assets {
+			sum.MultiAsset.set(policy, name, sum.MultiAsset.Quantity(policy, name)+quantity)
+		}
+	}
+
+	return sum
+}
+
+// Sub returns a new Value holding the difference of both values.
+// ErrInsufficientValue is returned if any lovelace or token quantity would become negative.
+func (v *Value) Sub(other *Value) (*Value, error) {
+	if other.Amount > v.Amount {
+		return nil, fmt.Errorf("%w: missing %d lovelace", ErrInsufficientValue, other.Amount-v.Amount)
+	}
+
+	diff := v.Clone()
+	diff.Amount -= other.Amount
+	for policy, assets := range other.MultiAsset {
+		for name, quantity := range assets {
+			available := diff.MultiAsset.Quantity(policy, name)
+			if quantity > available {
+				return nil, fmt.Errorf("%w: missing %d of asset %x.%x", ErrInsufficientValue, quantity-available, policy, name)
+			}
+			diff.MultiAsset.set(policy, name, available-quantity)
+		}
+	}
+
+	return diff, nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// The asset name is encoded as a byte string.
+func (n AssetName) MarshalCBOR() ([]byte, error) {
+	return cborEnc.Marshal([]byte(n))
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (n *AssetName) UnmarshalCBOR(data []byte) error {
+	var name []byte
+	if err := cborDec.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*n = AssetName(name)
+
+	return nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// A value without tokens is encoded as coin, otherwise as [coin, multiasset].
+func (v *Value) MarshalCBOR() ([]byte, error) {
+	if v.MultiAsset.IsZero() {
+		return cborEnc.Marshal(v.Amount)
+	}
+	return cborEnc.Marshal([]interface{}{v.Amount, v.MultiAsset.Clone()})
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (v *Value) UnmarshalCBOR(data []byte) error {
+	if len(data) > 0 && data[0]>>5 == cborMajorTypeArray {
+		var value struct {
+			_          struct{} `cbor:",toarray"`
+			Amount     uint
+			MultiAsset MultiAsset
+		}
+		if err := cborDec.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		v.Amount = value.Amount
+		v.MultiAsset = value.MultiAsset.Clone()
+
+		return nil
+	}
+
+	v.MultiAsset = MultiAsset{}
+	return cborDec.Unmarshal(data, &v.Amount)
+}
+
+// Quantity returns the quantity of the asset name under policy.
+func (ma MultiAsset) Quantity(policy crypto.ScriptHash, name AssetName) uint64 {
+	return ma[policy][name]
+}
+
+// IsZero reports whether all token quantities are zero.
+func (ma MultiAsset) IsZero() bool {
+	for _, assets := range ma {
+		for _, quantity := range assets {
+			if quantity != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Clone returns a deep copy of the multiasset.
+func (ma MultiAsset) Clone() MultiAsset {
+	clone := MultiAsset{}
+	for policy, assets := range ma {
+		for name, quantity := range assets {
+			clone.set(policy, name, quantity)
+		}
+	}
+	return clone
+}
+
+// set sets the quantity of an asset, dropping assets and policies that become empty.
+func (ma MultiAsset) set(policy crypto.ScriptHash, name AssetName, quantity uint64) {
+	if quantity == 0 {
+		delete(ma[policy], name)
+		if len(ma[policy]) == 0 {
+			delete(ma, policy)
+		}
+		return
+	}
+	if ma[policy] == nil {
+		ma[policy] = Assets{}
+	}
+	ma[policy][name] = quantity
+}
+
+// MintAssets maps asset names to the quantity minted (positive) or burned (negative).
+type MintAssets map[AssetName]int64
+
+// Mint maps policy ids to the assets minted or burned under the policy.
+type Mint map[crypto.ScriptHash]MintAssets
+
+// MintAsset is a quantity of a native token to mint (positive) or burn (negative).
+type MintAsset struct {
+	Name     AssetName
+	Quantity int64
+}
+
+// NewMintAsset returns a MintAsset of quantity tokens with the given name.
+func NewMintAsset(name string, quantity int64) MintAsset {
+	return MintAsset{
+		Name:     AssetName(name),
+		Quantity: quantity,
+	}
+}
+
+// Add adds quantity of the asset name under policy, dropping assets and policies that become zero.
+func (m Mint) Add(policy crypto.ScriptHash, name AssetName, quantity int64) {
+	total := m[policy][name] + quantity
+	if total == 0 {
+		delete(m[policy], name)
+		if len(m[policy]) == 0 {
+			delete(m, policy)
+		}
+		return
+	}
+	if m[policy] == nil {
+		m[policy] = MintAssets{}
+	}
+	m[policy][name] = total
+}
+
+// Minted returns the value of the tokens being minted.
+func (m Mint) Minted() *Value {
+	minted := NewValue(0)
+	for policy, assets := range m {
+		for name, quantity := range assets {
+			if quantity > 0 {
+				minted.MultiAsset.set(policy, name, uint64(quantity))
+			}
+		}
+	}
+	return minted
+}
+
+// Burned returns the value of the tokens being burned.
+func (m Mint) Burned() *Value {
+	burned := NewValue(0)
+	for policy, assets := range m {
+		for name, quantity := range assets {
+			if quantity < 0 {
+				burned.MultiAsset.set(policy, name, uint64(-quantity))
+			}
+		}
+	}
+	return burned
+}
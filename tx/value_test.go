@@ -0,0 +1,189 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/crypto"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPolicy(t *testing.T, hexPolicy string) crypto.ScriptHash {
+	t.Helper()
+	data, _ := hex.DecodeString(hexPolicy)
+	policy, err := crypto.ScriptHashFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func TestValueCBOR(t *testing.T) {
+	policy := testPolicy(t, "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+
+	coin := tx.NewValue(1000000)
+	data, err := coin.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "1a000f4240", hex.EncodeToString(data))
+
+	value := tx.NewValueWithAssets(1000000, tx.MultiAsset{
+		policy: tx.Assets{"token": 10, "a": 1, "zero": 0},
+	})
+	data, err = value.MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Asset names are sorted canonically (shorter first) and zero quantities are dropped
+	assert.Equal(t, "821a000f4240a1581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209a2416101"+
+		"45746f6b656e0a", hex.EncodeToString(data))
+
+	decoded := tx.Value{}
+	if err := decoded.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint(1000000), decoded.Amount)
+	assert.Equal(t, uint64(10), decoded.MultiAsset.Quantity(policy, "token"))
+	assert.Equal(t, uint64(1), decoded.MultiAsset.Quantity(policy, "a"))
+}
+
+func TestValueSub(t *testing.T) {
+	policy := testPolicy(t, "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+
+	value := tx.NewValueWithAssets(5, tx.MultiAsset{policy: tx.Assets{"token": 10}})
+	diff, err := value.Sub(tx.NewValueWithAssets(2, tx.MultiAsset{policy: tx.Assets{"token": 10}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint(3), diff.Amount)
+	assert.True(t, diff.MultiAsset.IsZero())
+	assert.Len(t, diff.MultiAsset, 0)
+
+	_, err = value.Sub(tx.NewValueWithAssets(0, tx.MultiAsset{policy: tx.Assets{"token": 11}}))
+	assert.ErrorIs(t, err, tx.ErrInsufficientValue)
+	_, err = value.Sub(tx.NewValue(6))
+	assert.ErrorIs(t, err, tx.ErrInsufficientValue)
+}
+
+func TestAddChangeIfNeededReturnsTokens(t *testing.T) {
+	policy := testPolicy(t, "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209")
+
+	addr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputWithValue(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0,
+		tx.NewValueWithAssets(10000000, tx.MultiAsset{policy: tx.Assets{"token": 100}}),
+	))
+	builder.AddOutputs(tx.NewTxOutputWithValue(
+		addr,
+		tx.NewValueWithAssets(2000000, tx.MultiAsset{policy: tx.Assets{"token": 40}}),
+	))
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := builder.Tx().Body.Outputs
+	assert.Len(t, outputs, 2)
+	change := outputs[1]
+	assert.Equal(t, uint64(60), change.MultiAsset.Quantity(policy, "token"))
+	assert.Equal(t, uint(10000000-2000000)-uint(builder.Tx().Body.Fee), change.Amount)
+
+	totalI, totalO := builder.GetTotalInputOutputValues()
+	assert.Equal(t, totalI.MultiAsset, totalO.MultiAsset)
+
+	data, err := builder.Tx().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint64(60), decoded.Body.Outputs[1].MultiAsset.Quantity(policy, "token"))
+}
+
+func TestMintAndBurn(t *testing.T) {
+	addr, prv, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyHash := prv.Public().PublicKey().Hash()
+	policy, err := tx.NewScriptPubKey(keyHash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := policy.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	policyID, err := crypto.ScriptHashFromBytes(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{prv})
+	builder.AddInputs(tx.NewTxInputWithValue(
+		"fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0,
+		tx.NewValueWithAssets(10000000, tx.MultiAsset{policyID: tx.Assets{"old": 50}}),
+	))
+	if err := builder.Mint(policy, tx.NewMintAsset("new", 100), tx.NewMintAsset("old", -20)); err != nil {
+		t.Fatal(err)
+	}
+	// Adding the same policy again does not duplicate the script
+	if err := builder.Mint(policy, tx.NewMintAsset("new", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+
+	txFinal, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, txFinal.WitnessSet.Scripts, 1)
+	assert.Len(t, txFinal.WitnessSet.Witnesses, 1)
+
+	change := txFinal.Body.Outputs[0]
+	assert.Equal(t, uint64(101), change.MultiAsset.Quantity(policyID, "new"))
+	assert.Equal(t, uint64(30), change.MultiAsset.Quantity(policyID, "old"))
+	assert.Equal(t, uint(10000000)-uint(txFinal.Body.Fee), change.Amount)
+
+	data, err := txFinal.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tx.Mint{policyID: tx.MintAssets{"new": 101, "old": -20}}, decoded.Body.Mint)
+	assert.Len(t, decoded.WitnessSet.Scripts, 1)
+}
+
+func TestMintCBOR(t *testing.T) {
+	body := tx.NewTxBody()
+	body.Mint = tx.Mint{
+		testPolicy(t, "f0ff48bbb7bbe9d59a40f1ce90e9e9d0ff5002ec48f232b49ca0fb9a"): tx.MintAssets{"b": -1, "a": 2},
+		testPolicy(t, "1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209"): tx.MintAssets{"": 1},
+	}
+
+	// Policies and asset names are sorted, so every encoding of the body is the same
+	for i := 0; i < 10; i++ {
+		hexBody, err := body.Hex()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "a400800180020009a2"+
+			"581c1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209a14001"+
+			"581cf0ff48bbb7bbe9d59a40f1ce90e9e9d0ff5002ec48f232b49ca0fb9aa2416102416220", hexBody)
+	}
+}
@@ -0,0 +1,59 @@
+package tx
+
+import (
+	"fmt"
+
+	"github.com/milos-ethernal/go-cardano-serialization/address"
+)
+
+// RewardAccount is the raw bytes of a reward address.
+type RewardAccount string
+
+// NewRewardAccount returns the RewardAccount of the reward address.
+func NewRewardAccount(addr *address.RewardAddress) RewardAccount {
+	return RewardAccount(addr.Bytes())
+}
+
+// Address returns the reward address of the account.
+func (r RewardAccount) Address() (*address.RewardAddress, error) {
+	if len(r) == 0 {
+		return nil, fmt.Errorf("empty reward account")
+	}
+	addr, err := address.NewAddressFromBytes([]byte(r))
+	if err != nil {
+		return nil, err
+	}
+	rewardAddr, ok := addr.(*address.RewardAddress)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a reward address", addr)
+	}
+	return rewardAddr, nil
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+// The reward account is encoded as a byte string.
+func (r RewardAccount) MarshalCBOR() ([]byte, error) {
+	return cborEnc.Marshal([]byte(r))
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (r *RewardAccount) UnmarshalCBOR(data []byte) error {
+	var account []byte
+	if err := cborDec.Unmarshal(data, &account); err != nil {
+		return err
+	}
+	*r = RewardAccount(account)
+
+	return nil
+}
+
+// Withdrawals maps reward accounts to the lovelace withdrawn from them.
+type Withdrawals map[RewardAccount]uint
+
+// Total returns the total lovelace withdrawn.
+func (w Withdrawals) Total() (total uint) {
+	for _, amount := range w {
+		total += amount
+	}
+	return
+}
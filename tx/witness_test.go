@@ -0,0 +1,188 @@
+package tx_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/milos-ethernal/go-cardano-serialization/bip32"
+	"github.com/milos-ethernal/go-cardano-serialization/network"
+	"github.com/milos-ethernal/go-cardano-serialization/tx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartiallySignedTx(t *testing.T) {
+	pr := loadTestProtocol(t)
+	changeAddr, _, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountKey := createRootKey().Derive(harden(1852)).Derive(harden(1815)).Derive(harden(0))
+	keys := []bip32.XPrv{accountKey.Derive(0).Derive(0), accountKey.Derive(0).Derive(1), accountKey.Derive(0).Derive(2)}
+
+	// 2 of 3 multisig
+	script := tx.NativeScript{Type: tx.ScriptNofK, N: 2}
+	for _, key := range keys {
+		keyHash := key.Public().PublicKey().Hash()
+		script.Scripts = append(script.Scripts, tx.NativeScript{Type: tx.ScriptPubKey, KeyHash: keyHash[:]})
+	}
+	scriptAddr, err := tx.NewScriptEnterpriseAddress(network.TestNet(), &script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxos := []*tx.TxInput{
+		tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(scriptAddr, 10000000)),
+	}
+
+	// the coordinator builds and exports the unsigned transaction
+	builder := tx.NewTxBuilder(pr, []bip32.XPrv{})
+	if err := builder.AddScriptInput(utxos[0], script); err != nil {
+		t.Fatal(err)
+	}
+	builder.AddOutputs(tx.NewTxOutput(changeAddr, 2000000))
+	if err := builder.AddChangeIfNeeded(changeAddr); err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := builder.BuildUnsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, unsigned.WitnessSet.Witnesses)
+	unsignedBytes, err := unsigned.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each party signs the transaction on its own
+	witnessSets := [][]byte{}
+	for _, key := range keys[1:] {
+		partyTx, err := tx.NewTxFromBytes(unsignedBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		witnessSet, err := partyTx.SignWitnessSet(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := witnessSet.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		witnessSets = append(witnessSets, data)
+	}
+
+	// the coordinator merges the witnesses, a party having sent its witness twice
+	final, err := tx.NewTxFromBytes(unsignedBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range append(witnessSets, witnessSets[0]) {
+		witnessSet, err := tx.NewWitnessSetFromBytes(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := final.AddWitnesses(witnessSet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Len(t, final.WitnessSet.Witnesses, 2)
+	assert.Len(t, final.WitnessSet.Scripts, 1)
+	assert.NoError(t, final.VerifyWitnesses())
+
+	unsignedHash, err := unsigned.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	finalHash, err := final.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, unsignedHash, finalHash)
+
+	violations, err := tx.Validate(final, utxos, pr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, violations)
+
+	// witnesses of another transaction are rejected
+	builder.SetTTL(1000)
+	other, err := builder.BuildUnsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherWitnesses, err := other.SignWitnessSet(keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ErrorIs(t, final.AddWitnesses(otherWitnesses), tx.ErrInvalidWitness)
+	assert.Len(t, final.WitnessSet.Witnesses, 2)
+
+	final.WitnessSet.Witnesses[0].Signature[0] ^= 0xff
+	assert.ErrorIs(t, final.VerifyWitnesses(), tx.ErrInvalidWitness)
+}
+
+func TestAddWitnessesKeepsPlutusData(t *testing.T) {
+	addr, key, err := generateBaseAddress(network.TestNet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := tx.NewTxBuilder(loadTestProtocol(t), []bip32.XPrv{})
+	builder.AddInputs(tx.NewTxInputFromOutput("fcbc18c64cdf133f33dd319c5105dc7c4972f2d646ae276fbd00cf7f39f8c380", 0, tx.NewTxOutput(addr, 10000000)))
+	if err := builder.AddChangeIfNeeded(addr); err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := builder.BuildUnsigned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsignedBytes, err := unsigned.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a transaction of a third party with a tag 258 set of datums and Conway map redeemers
+	datums, _ := hex.DecodeString("d9010281182a")
+	redeemers, _ := hex.DecodeString("a1820000821840821903e81907d0")
+	witnessSet, err := cbor.Marshal(map[uint64]cbor.RawMessage{4: datums, 5: redeemers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []cbor.RawMessage
+	if err := cbor.Unmarshal(unsignedBytes, &fields); err != nil {
+		t.Fatal(err)
+	}
+	fields[1] = witnessSet
+	data, err := cbor.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := tx.NewTxFromBytes(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnesses, err := decoded.SignWitnessSet(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.AddWitnesses(witnesses); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := decoded.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cbor.Unmarshal(signed, &fields); err != nil {
+		t.Fatal(err)
+	}
+	var signedWitnessSet map[uint64]cbor.RawMessage
+	if err := cbor.Unmarshal(fields[1], &signedWitnessSet); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, signedWitnessSet, 3)
+	assert.Equal(t, cbor.RawMessage(datums), signedWitnessSet[4])
+	assert.Equal(t, cbor.RawMessage(redeemers), signedWitnessSet[5])
+	assert.NoError(t, decoded.VerifyWitnesses())
+}